@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"mp/sozzler/pkg/display"
+	"mp/sozzler/pkg/sozzler"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch <recipe name>",
+	Short: "Scale a recipe up to a pre-mixed batch",
+	Long: `Scale a recipe up to a batch to mix ahead of time, by the volume of the batch
+(--volume 750ml) or by the number of drinks (--servings 20).
+
+Ingredients that don't keep, like fresh juice, soda, and egg, and unmeasured
+ones like garnishes, are left out of the batch and listed with the amounts to
+add to each drink. Those are ingredients with words from --unbatchable, and
+fresh ingredients in the ingredient database.
+
+With --dilution, the batch includes water, so drinks can be served straight
+from the bottle without shaking or stirring with ice.`,
+
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		catalog := cmd.Context().Value(catalogKey{}).(*sozzler.RecipeCatalog)
+		display := cmd.Context().Value(displayKey{}).(display.Display)
+
+		name := args[0]
+		recipe, err := catalog.Lookup(name)
+		if err != nil {
+			return err
+		}
+
+		opts, err := batchOptions(cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		opts.Ingredients = catalog.Ingredients
+		batch, err := recipe.Batch(opts)
+		if err != nil {
+			return err
+		}
+
+		units, _ := cmd.Flags().GetString("units")
+		batch.Recipe.Notes = batchNotes(batch, sozzler.System(units), recipe.Notes)
+		display.Show(batch.Recipe)
+		return nil
+	},
+}
+
+// batchOptions reads the batch command's flags.
+func batchOptions(flags *pflag.FlagSet) (sozzler.BatchOptions, error) {
+	var opts sozzler.BatchOptions
+
+	if flags.Changed("volume") == flags.Changed("servings") {
+		return opts, fmt.Errorf("use one of --volume or --servings")
+	}
+
+	volume, err := flags.GetString("volume")
+	if err != nil {
+		return opts, fmt.Errorf("error reading volume flag: %w", err)
+	}
+	if flags.Changed("volume") {
+		q, u, err := sozzler.ParseMeasure(volume)
+		if err != nil {
+			return opts, err
+		}
+		opts.Volume, opts.VolumeUnit = q, u.Name
+	}
+
+	opts.Servings, err = flags.GetInt("servings")
+	if err != nil {
+		return opts, fmt.Errorf("error reading servings flag: %w", err)
+	}
+	if flags.Changed("servings") && opts.Servings <= 0 {
+		return opts, fmt.Errorf("invalid servings %d: use a positive number", opts.Servings)
+	}
+
+	dilution, err := flags.GetString("dilution")
+	if err != nil {
+		return opts, fmt.Errorf("error reading dilution flag: %w", err)
+	}
+	if flags.Changed("dilution") {
+		if opts.Dilution, err = parseDilution(dilution); err != nil {
+			return opts, err
+		}
+	}
+
+	opts.Unbatchable, err = flags.GetStringSlice("unbatchable")
+	if err != nil {
+		return opts, fmt.Errorf("error reading unbatchable flag: %w", err)
+	}
+
+	return opts, nil
+}
+
+// parseDilution parses a dilution written as a percentage, like "20%", or a
+// fraction, like "1/5" or "0.2".
+func parseDilution(s string) (*big.Rat, error) {
+	percent := strings.HasSuffix(s, "%")
+	d, ok := new(big.Rat).SetString(strings.TrimSpace(strings.TrimSuffix(s, "%")))
+	if !ok || d.Sign() < 0 {
+		return nil, fmt.Errorf("invalid dilution %q: use a percentage like 20%% or a fraction like 1/5", s)
+	}
+	if percent {
+		d.Quo(d, big.NewRat(100, 1))
+	}
+	return d, nil
+}
+
+// batchNotes describes how many drinks batch makes and what to add to each,
+// followed by the recipe's own notes.
+func batchNotes(batch *sozzler.Batch, units sozzler.System, notes string) string {
+	servings := sozzler.NewQuantity(batch.Servings.Num().Int64(), batch.Servings.Denom().Int64())
+
+	var b strings.Builder
+	if batch.Servings.IsInt() {
+		fmt.Fprintf(&b, "Makes %s drinks.", servings)
+	} else {
+		fmt.Fprintf(&b, "Makes about %s drinks.", servings.Round(big.NewRat(1, 1)))
+	}
+	if len(batch.Unbatched) > 0 {
+		b.WriteString(" Add to each drink:\n")
+		for _, c := range sozzler.FancyOrder(sozzler.InSystem(batch.Unbatched, units)) {
+			b.WriteString("  " + strings.Join(strings.Fields(fmt.Sprint(c.Quantity, " ", c.Unit, " ", c.Ingredient)), " ") + "\n")
+		}
+	}
+	if notes != "" {
+		b.WriteString("\n" + notes)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+	batchCmd.Flags().String("volume", "", "make a batch of this size, like 750ml or 25oz")
+	batchCmd.Flags().Int("servings", 0, "make a batch of this many drinks")
+	batchCmd.Flags().String("dilution", "", "add water to the batch, like 20% or 1/5")
+	batchCmd.Flags().StringSlice("unbatchable", sozzler.DefaultUnbatchable, "leave out ingredients with these words")
+}
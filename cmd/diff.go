@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unifiedDiff returns a unified diff from a to b with three lines of context, or
+// "" if they're the same. Recipe files are small, so it uses a plain LCS table.
+func unifiedDiff(aName, bName string, a, b []byte) string {
+	al, bl := splitLines(string(a)), splitLines(string(b))
+
+	// lcs[i][j] is the length of the longest common subsequence of al[i:], bl[j:]
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type edit struct {
+		op   byte
+		line string
+		i, j int // line numbers before this edit in a and b
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < len(al) || j < len(bl) {
+		switch {
+		case i < len(al) && j < len(bl) && al[i] == bl[j]:
+			edits = append(edits, edit{' ', al[i], i, j})
+			i, j = i+1, j+1
+		case i < len(al) && (j == len(bl) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', al[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', bl[j], i, j})
+			j++
+		}
+	}
+
+	const context = 3
+	var out strings.Builder
+	for start := 0; start < len(edits); {
+		// find the next change, then extend the hunk until changes are more than
+		// two contexts apart
+		first := start
+		for first < len(edits) && edits[first].op == ' ' {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		last := first
+		for k := first; k < len(edits); k++ {
+			if edits[k].op != ' ' {
+				last = k
+			} else if k-last > 2*context {
+				break
+			}
+		}
+
+		from, to := max(first-context, start), min(last+context+1, len(edits))
+
+		aLines, bLines := 0, 0
+		for _, e := range edits[from:to] {
+			if e.op != '+' {
+				aLines++
+			}
+			if e.op != '-' {
+				bLines++
+			}
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(edits[from].i, aLines), hunkRange(edits[from].j, bLines))
+		for _, e := range edits[from:to] {
+			fmt.Fprintf(&out, "%c%s", e.op, e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		start = to
+	}
+
+	return out.String()
+}
+
+func hunkRange(start, lines int) string {
+	if lines == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if lines == 1 {
+		return fmt.Sprint(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, lines)
+}
+
+// splitLines splits s into lines, keeping their newlines, so a last line
+// without one differs from the same line with one.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		desc string
+		a, b string
+		want string
+	}{
+		{
+			desc: "identical",
+			a:    "1\n2\n3\n",
+			b:    "1\n2\n3\n",
+			want: "",
+		},
+		{
+			desc: "change at the start",
+			a:    "x\n1\n2\n3\n4\n5\n6\n",
+			b:    "y\n1\n2\n3\n4\n5\n6\n",
+			want: "@@ -1,4 +1,4 @@\n-x\n+y\n 1\n 2\n 3\n",
+		},
+		{
+			desc: "change at the end",
+			a:    "1\n2\n3\n4\n5\n6\nx\n",
+			b:    "1\n2\n3\n4\n5\n6\ny\n",
+			want: "@@ -4,4 +4,4 @@\n 4\n 5\n 6\n-x\n+y\n",
+		},
+		{
+			desc: "missing trailing newline",
+			a:    "1\n2\n3\n",
+			b:    "1\n2\n3",
+			want: "@@ -1,3 +1,3 @@\n 1\n 2\n-3\n+3\n\\ No newline at end of file\n",
+		},
+		{
+			desc: "close changes merge",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "1\nX\n3\n4\n5\n6\n7\n8\nY\n10\n",
+			want: "@@ -1,10 +1,10 @@\n 1\n-2\n+X\n 3\n 4\n 5\n 6\n 7\n 8\n-9\n+Y\n 10\n",
+		},
+		{
+			desc: "far changes don't",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			b:    "1\nX\n3\n4\n5\n6\n7\n8\n9\n10\nY\n",
+			want: "@@ -1,5 +1,5 @@\n 1\n-2\n+X\n 3\n 4\n 5\n@@ -8,4 +8,4 @@\n 8\n 9\n 10\n-11\n+Y\n",
+		},
+		{
+			desc: "added line",
+			a:    "a\n",
+			b:    "a\nb\n",
+			want: "@@ -1 +1,2 @@\n a\n+b\n",
+		},
+		{
+			desc: "from nothing",
+			a:    "",
+			b:    "a\n",
+			want: "@@ -0,0 +1 @@\n+a\n",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			want := tC.want
+			if want != "" {
+				want = "--- a\n+++ b\n" + want
+			}
+			assert.Equal(t, want, unifiedDiff("a", "b", []byte(tC.a), []byte(tC.b)))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+)
+
+const recipesEnv = "SOZZLER_RECIPES"
+
+// recipeDirs resolves the recipe directories to load, in this order:
+//
+//  1. --recipes-dir, which may be repeated
+//  2. $SOZZLER_RECIPES, a list separated like $PATH
+//  3. $XDG_DATA_HOME/sozzler/recipes (default ~/.local/share/sozzler/recipes)
+//  4. ./recipes
+//
+// The first source that names any directories wins. When more than one
+// directory is named, later directories override earlier ones on recipe name
+// collisions.
+func recipeDirs(flags *pflag.FlagSet) ([]string, error) {
+	dirs, err := flags.GetStringSlice("recipes-dir")
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) > 0 {
+		return dirs, nil
+	}
+
+	if env := os.Getenv(recipesEnv); env != "" {
+		for _, dir := range filepath.SplitList(env) {
+			if dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+		if len(dirs) > 0 {
+			return dirs, nil
+		}
+	}
+
+	if dir := xdgDataDir("recipes"); dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return []string{dir}, nil
+		}
+	}
+
+	return []string{"./recipes"}, nil
+}
+
+// xdgDataDir returns elem inside sozzler's XDG data directory, or "" if no home
+// directory can be determined.
+func xdgDataDir(elem ...string) string {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(append([]string{base, "sozzler"}, elem...)...)
+}
+
+// xdgConfigDir returns elem inside sozzler's XDG config directory, or "" if no
+// home directory can be determined.
+func xdgConfigDir(elem ...string) string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(append([]string{base, "sozzler"}, elem...)...)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"mp/sozzler/pkg/display"
+	"mp/sozzler/pkg/sozzler"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <recipe name>",
+	Short: "Edit a recipe in $VISUAL or $EDITOR",
+	Long: `Open the file of the named recipe in $VISUAL, $EDITOR, or vi, then check it
+when the editor exits. If the recipe is broken, offer to open it again.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		catalog := cmd.Context().Value(catalogKey{}).(*sozzler.RecipeCatalog)
+		display := cmd.Context().Value(displayKey{}).(display.Display)
+
+		name := args[0]
+		filename, err := recipeFile(catalog, name)
+		if err != nil {
+			return err
+		}
+
+		in := bufio.NewReader(os.Stdin)
+		for {
+			if err := runEditor(filename); err != nil {
+				return err
+			}
+
+			diagnostics, err := sozzler.LintFile(filename)
+			if err != nil {
+				return err
+			}
+
+			broken := false
+			for _, d := range diagnostics {
+				display.Error(d.String())
+				if d.Severity == sozzler.SeverityError {
+					broken = true
+				}
+			}
+			if !broken {
+				return nil
+			}
+
+			display.String("Recipe has errors. Open it again? [Y/n] ")
+			answer, err := in.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			if err != nil || (answer != "" && !strings.HasPrefix(answer, "y")) {
+				display.String("\n")
+				return fmt.Errorf("recipe %q in %s has errors", name, filename)
+			}
+		}
+	},
+}
+
+// recipeFile finds the file of the named recipe. A recipe too broken to load
+// is found by its file name, ignoring case, anywhere in the directories the
+// catalog loaded, the last of them first, as its recipes win, so it can be
+// fixed.
+func recipeFile(catalog *sozzler.RecipeCatalog, name string) (string, error) {
+	if recipe, ok := catalog.Find(name); ok {
+		if recipe.Path == "" {
+			return "", fmt.Errorf("recipe %q has no file to edit", recipe.Name)
+		}
+		return recipe.Path, nil
+	}
+
+	base := sozzler.RecipeFilename(name)
+	for i := len(catalog.Dirs) - 1; i >= 0 && base != ""; i-- {
+		if filename := findFile(catalog.Dirs[i], base); filename != "" {
+			return filename, nil
+		}
+	}
+
+	return "", fmt.Errorf("couldn't find recipe %q", name)
+}
+
+// findFile finds the recipe file called base, ignoring case, in dir or below
+// it, skipping hidden files and directories, and the ingredients file. It
+// returns "" if there's none.
+func findFile(dir, base string) string {
+	var found string
+	_ = filepath.WalkDir(dir, func(filename string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// unreadable files and directories can't be edited anyway
+			return nil
+		}
+		hidden := filename != dir && strings.HasPrefix(entry.Name(), ".")
+		if entry.IsDir() {
+			if hidden {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if hidden || (entry.Name() == sozzler.IngredientsFile && filepath.Dir(filename) == filepath.Clean(dir)) {
+			return nil
+		}
+		if strings.EqualFold(entry.Name(), base) {
+			found = filename
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// runEditor edits filename with $VISUAL, $EDITOR, or vi. The variable may
+// include arguments, like "code --wait".
+func runEditor(filename string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	args := strings.Fields(editor)
+	c := exec.Command(args[0], append(args[1:], filename)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("couldn't run editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
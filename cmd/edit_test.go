@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeFile(t *testing.T) {
+	shared, mine := t.TempDir(), t.TempDir()
+	write := func(dir, name, data string) {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		require.NoError(t, os.WriteFile(filename, []byte(data), 0o644))
+	}
+	write(shared, "Daiquiri.yaml", "name: Daiquiri\n")
+	write(shared, "tiki/Mai Tai.yaml", "name: [Mai Tai\n")
+	write(shared, "Zombie.yaml", "name: [Zombie\n")
+	write(shared, ".trash/Negroni.yaml", "name: [Negroni\n")
+	write(shared, "ingredients.yaml", "- name: falernum\n")
+	write(mine, "stirred/zombie.yaml", "name: [Zombie\n")
+
+	catalog := &sozzler.RecipeCatalog{Lenient: true}
+	require.Error(t, catalog.Load(shared, mine))
+
+	testCases := []struct {
+		name string
+		want string
+	}{
+		{"daiquiri", filepath.Join(shared, "Daiquiri.yaml")},
+		{"Mai Tai", filepath.Join(shared, "tiki", "Mai Tai.yaml")},
+		{"Zombie", filepath.Join(mine, "stirred", "zombie.yaml")},
+		{"Negroni", ""},
+		{"Ingredients", ""},
+		{"Martini", ""},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			got, err := recipeFile(catalog, tC.name)
+			if tC.want == "" {
+				assert.EqualError(t, err, `couldn't find recipe "`+tC.name+`"`)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tC.want, got)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"mp/sozzler/pkg/sozzler"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const exclusionsEnv = "SOZZLER_EXCLUSIONS"
+
+// exclusionsPath resolves the exclusions file, in this order:
+//
+//  1. $SOZZLER_EXCLUSIONS
+//  2. $XDG_CONFIG_HOME/sozzler/exclusions.yaml (default ~/.config/sozzler/exclusions.yaml)
+//
+// It returns "" if there's nowhere to look.
+func exclusionsPath() string {
+	if env := os.Getenv(exclusionsEnv); env != "" {
+		return env
+	}
+	return xdgConfigDir("exclusions.yaml")
+}
+
+// loadExclusions loads the exclusions file, unless --no-exclusions is set, and
+// adds the exclusions named by the flags. A missing exclusions file excludes
+// nothing.
+func loadExclusions(flags *pflag.FlagSet) (*sozzler.Exclusions, error) {
+	ex := &sozzler.Exclusions{}
+
+	skip, err := flags.GetBool("no-exclusions")
+	if err != nil {
+		return nil, fmt.Errorf("error reading no-exclusions flag: %w", err)
+	}
+	if path := exclusionsPath(); !skip && path != "" {
+		profile, err := sozzler.LoadExclusions(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if profile != nil {
+			ex.Add(profile)
+		}
+	}
+
+	ingredients, err := flags.GetStringSlice("exclude-ingredients")
+	if err != nil {
+		return nil, fmt.Errorf("error reading exclude-ingredients flag: %w", err)
+	}
+	allergens, err := flags.GetStringSlice("exclude-allergen")
+	if err != nil {
+		return nil, fmt.Errorf("error reading exclude-allergen flag: %w", err)
+	}
+	ex.Add(&sozzler.Exclusions{Ingredients: ingredients, Allergens: allergens})
+	return ex, nil
+}
+
+// excludeRecipes returns catalog without the recipes excluded by the
+// exclusions file and flags.
+func excludeRecipes(flags *pflag.FlagSet, catalog *sozzler.RecipeCatalog) (*sozzler.RecipeCatalog, error) {
+	ex, err := loadExclusions(flags)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ex.Predicate(catalog.Ingredients)
+	if err != nil || p == nil {
+		return catalog, err
+	}
+	return catalog.Filter(p), nil
+}
+
+// addExcludeFlags adds the flags that exclude recipes to cmd.
+func addExcludeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("exclude-ingredients", []string{}, "leave out recipes with these ingredients, comma separated, matched by whole words, like egg for Egg White, or by kind, like whiskey for Bourbon")
+	cmd.Flags().StringSlice("exclude-allergen", []string{}, "leave out recipes with ingredients tagged with these allergens, like nuts, egg, or dairy")
+	cmd.Flags().Bool("no-exclusions", false, "ignore the exclusions file ($"+exclusionsEnv+", then $XDG_CONFIG_HOME/sozzler/exclusions.yaml)")
+}
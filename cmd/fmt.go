@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"mp/sozzler/pkg/sozzler"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [file or directory...]",
+	Short: "Rewrite recipe files in canonical form",
+	Long: `Rewrite recipe files in canonical form: keys in a fixed order, quantities as
+reduced fractions, unmeasured quantities and empty units left out, and
+multi-line notes as block scalars.
+
+With no arguments, formats every .yaml file in the recipe directories.`,
+	SilenceUsage: true,
+
+	// fmt reports broken recipes itself, so it doesn't load the catalog.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		check, _ := cmd.Flags().GetBool("check")
+		diff, _ := cmd.Flags().GetBool("diff")
+
+		if len(args) == 0 {
+			dirs, err := recipeDirs(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			args = dirs
+		}
+
+		files, err := recipeFiles(args)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		var failed, unformatted int
+		for _, filename := range files {
+			src, err := os.ReadFile(filename)
+			if err != nil {
+				return err
+			}
+
+			formatted, err := sozzler.Format(src)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+				failed++
+				continue
+			}
+			if bytes.Equal(src, formatted) {
+				continue
+			}
+			unformatted++
+
+			if check {
+				_, _ = fmt.Fprintln(out, filename)
+			}
+			if diff {
+				_, _ = fmt.Fprint(out, unifiedDiff(filename+".orig", filename, src, formatted))
+			}
+			if check || diff {
+				continue
+			}
+
+			info, err := os.Stat(filename)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filename, formatted, info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d recipe files couldn't be formatted", failed)
+		}
+		if check && unformatted > 0 {
+			return fmt.Errorf("%d recipe files aren't formatted", unformatted)
+		}
+		return nil
+	},
+}
+
+// recipeFiles expands paths into the .yaml files they name, descending into
+// directories and skipping hidden ones, and the ingredients file at the top of
+// each directory, which isn't a recipe.
+func recipeFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(filename string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if filename != path && strings.HasPrefix(entry.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filename != path && entry.Name() == sozzler.IngredientsFile && filepath.Dir(filename) == filepath.Clean(path) {
+				return nil
+			}
+			if filename == path || filepath.Ext(filename) == ".yaml" {
+				files = append(files, filename)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func init() {
+	rootCmd.AddCommand(fmtCmd)
+	fmtCmd.Flags().BoolP("check", "l", false, "list files that aren't formatted, without rewriting them")
+	fmtCmd.Flags().BoolP("diff", "d", false, "show a diff of the formatting changes, without rewriting them")
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		require.NoError(t, os.WriteFile(filename, []byte(data), 0o644))
+	}
+	write("ingredients.yaml", "- name: falernum\n  category: liqueur\n")
+	write("Daiquiri.yaml", "name: Daiquiri\nrating: 0\ncomponents:\n  - ingredient: rum\n    quantity: \"2\"\n    unit: oz\n")
+	write("tiki/ingredients.yaml", "name: Ingredients\n")
+	write(".git/config.yaml", "not: a recipe\n")
+	write("README.md", "# Recipes\n")
+
+	files, err := recipeFiles([]string{dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "Daiquiri.yaml"),
+		filepath.Join(dir, "tiki", "ingredients.yaml"),
+	}, files, "only the ingredients file at the top isn't a recipe")
+
+	files, err = recipeFiles([]string{filepath.Join(dir, "ingredients.yaml")})
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "files named outright are formatted")
+
+	// fmt --check with no files formats the recipe directories
+	require.NoError(t, os.Remove(filepath.Join(dir, "tiki", "ingredients.yaml")))
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"fmt", "--check", "--recipes-dir", dir})
+	require.NoError(t, rootCmd.Execute())
+	assert.Empty(t, out.String())
+}
@@ -2,18 +2,20 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"mp/sozzler/pkg/sozzler"
 	"os"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var importCmd = &cobra.Command{
 	Use:   "import",
-	Short: "Parse a recipe from stdin into Sozzler Recipe YAML format and print it to stdout",
-	Long: `Parse a recipe from standard input (example follows) and print a YAML representation to stdout.
+	Short: "Parse a recipe from stdin into Sozzler Recipe YAML format and print or save it",
+	Long: `Parse a recipe from standard input (example follows) and print a YAML representation to stdout,
+or with --save, write it to the recipe directory. When several recipe directories are in use,
+recipes are saved to the last one.
 
 Example input (between the --- lines):
 ---
@@ -42,15 +44,33 @@ Shake ingredients with ice. Stir ingredients with dry ice. Correct spelling erro
 			return fmt.Errorf("error parsing markdown: %w", err)
 		}
 
-		enc := yaml.NewEncoder(os.Stdout)
-		// enc.SetIndent("", "  ")
-		if err := enc.Encode(recipe); err != nil {
-			return fmt.Errorf("encoding JSON: %w", err)
+		save, _ := cmd.Flags().GetBool("save")
+		if !save {
+			return sozzler.EncodeRecipe(os.Stdout, recipe)
 		}
+
+		catalog := cmd.Context().Value(catalogKey{}).(*sozzler.RecipeCatalog)
+
+		force, _ := cmd.Flags().GetBool("force")
+		if force {
+			err = catalog.Replace(recipe)
+		} else {
+			err = catalog.Save(recipe)
+		}
+		if errors.Is(err, sozzler.ErrRecipeExists) {
+			return fmt.Errorf("%w: use --force to replace it", err)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("saved", recipe.Path)
 		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().Bool("save", false, "save the recipe to the recipe directory instead of printing it")
+	importCmd.Flags().BoolP("force", "f", false, "with --save, replace an existing recipe with the same name")
 }
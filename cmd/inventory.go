@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"mp/sozzler/pkg/sozzler"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const inventoryEnv = "SOZZLER_INVENTORY"
+
+// inventoryPath resolves the inventory file, in this order:
+//
+//  1. --inventory
+//  2. $SOZZLER_INVENTORY
+//  3. $XDG_CONFIG_HOME/sozzler/inventory.yaml (default ~/.config/sozzler/inventory.yaml)
+func inventoryPath(flags *pflag.FlagSet) (string, error) {
+	path, err := flags.GetString("inventory")
+	if err != nil {
+		return "", fmt.Errorf("error reading inventory flag: %w", err)
+	}
+	if path != "" {
+		return path, nil
+	}
+	if env := os.Getenv(inventoryEnv); env != "" {
+		return env, nil
+	}
+	if path := xdgConfigDir("inventory.yaml"); path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("couldn't find an inventory: use --inventory or $%s", inventoryEnv)
+}
+
+// loadInventory loads the inventory file named by the flags. If it isn't
+// required, and the flags don't name one, a missing inventory is nil.
+func loadInventory(flags *pflag.FlagSet, required bool) (*sozzler.Inventory, error) {
+	path, err := inventoryPath(flags)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := sozzler.LoadInventory(path)
+	if errors.Is(err, fs.ErrNotExist) && !required && !flags.Changed("inventory") {
+		return nil, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("couldn't find inventory %q: list the ingredients you have there, or use --inventory", path)
+	}
+	return inv, err
+}
+
+// addInventoryFlag adds the --inventory flag to cmd.
+func addInventoryFlag(cmd *cobra.Command) {
+	cmd.Flags().String("inventory", "", "inventory file (default $"+inventoryEnv+", then $XDG_CONFIG_HOME/sozzler/inventory.yaml)")
+}
@@ -6,6 +6,7 @@ import (
 	"mp/sozzler/pkg/sozzler"
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -13,53 +14,160 @@ import (
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List and search recipes",
-	Args:  cobra.NoArgs,
+	Long: `List recipes, or search them. Recipes matching any of the flags are listed.
+
+--query combines terms with and, or, not, and parentheses:
+
+  ingredient:gin     an ingredient contains gin; also i:gin
+  name:"mai tai"     the name contains mai tai; also n:
+  rating>=4          rated 4 or more; also >, <, <=, =, and rating:4 for >=
+  lime               the name or an ingredient contains lime
+
+If nothing matches, ingredients and names are tried again allowing a typo or
+two, so -i simpel finds Simple Syrup.
+
+Terms side by side are anded, and "and" binds tighter than "or":
+
+  sozzler list -q 'ingredient:gin and ingredient:lime and not ingredient:egg and rating>=4'
+
+--text searches the notes, for recipes with every word in them, in any form:
+"pairing" finds "pairs" and "paired". Words in quotes must be side by side:
+
+  sozzler list --text '"dark chocolate" coupe'
+
+Recipes with ingredients you don't want are left out, with
+--exclude-ingredients, --exclude-allergen, or an exclusions file, like
+
+  ingredients: [egg white, orgeat]
+  allergens: [nuts]
+
+in $SOZZLER_EXCLUSIONS or $XDG_CONFIG_HOME/sozzler/exclusions.yaml.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		catalog := cmd.Context().Value(catalogKey{}).(*sozzler.RecipeCatalog)
 		display := cmd.Context().Value(displayKey{}).(display.Display)
 
 		verbose, _ := cmd.Flags().GetBool("verbose")
+		tui, _ := cmd.Flags().GetBool("tui")
+
+		plain, _ := cmd.Flags().GetBool("plain")
 
-		predicates, err := makePredicates(cmd.Flags())
+		catalog, err := excludeRecipes(cmd.Flags(), catalog)
+		if err != nil {
+			return err
+		}
+
+		predicates, err := makePredicates(cmd.Flags(), catalog)
+		if err != nil {
+			return err
+		}
+
+		order, reverse, err := sortOrder(cmd.Flags(), len(predicates) > 0)
 		if err != nil {
 			return err
 		}
 
 		if len(predicates) == 0 {
-			display.List(catalog.Recipes)
+			recipes := append([]*sozzler.Recipe(nil), catalog.Recipes...)
+			sozzler.SortRecipes(recipes, order, reverse)
+			display.List(recipes)
 
 			return nil
 		}
 
 		results := catalog.Search(predicates)
+		sozzler.SortResults(results, order, reverse)
 
 		if len(results) == 0 {
 			display.String("no results\n")
 			return nil
 		}
 
-		for recipe, result := range results {
-			var matches []string
-			for _, m := range result {
-				matches = append(matches, fmt.Sprintf("%s (%s)", m.Match, m.Predicate.Name()))
+		if tui {
+			var recipes []*sozzler.Recipe
+			for _, result := range results {
+				recipes = append(recipes, result.Recipe)
 			}
-			fancyMatches := strings.Join(matches, ", ")
+			display.List(recipes)
+			return nil
+		}
 
-			display.String(recipe.Name + ": " + fancyMatches + "\n")
+		for _, result := range results {
+			var matches []string
+			for _, m := range result.Matches {
+				match := m.Match
+				if !plain {
+					match = highlight(m)
+				}
+				if verbose {
+					matches = append(matches, fmt.Sprintf("%s (%s %.2f)", match, m.Predicate.Name(), m.Score))
+				} else {
+					matches = append(matches, fmt.Sprintf("%s (%s)", match, m.Predicate.Name()))
+				}
+			}
+			name := result.Recipe.Name
+			if verbose {
+				name += fmt.Sprintf(" [%.2f]", result.Score)
+			}
+			if len(matches) == 0 {
+				// only matched by not
+				display.String(name + "\n")
+			} else {
+				display.String(name + ": " + strings.Join(matches, ", ") + "\n")
+			}
 			if verbose {
-				display.Show(recipe)
+				display.Show(result.Recipe)
 				display.String("\n")
 			}
 		}
 
-		// FIXME: ensure sort order
-		// FIXME: flags to change sort order
-
 		return nil
 	},
 }
 
-func makePredicates(flags *pflag.FlagSet) ([]sozzler.Predicate, error) {
+// sortOrder reads the sort flags. Without --sort, search results are sorted
+// by match, and everything else by name.
+func sortOrder(flags *pflag.FlagSet, searching bool) (sozzler.SortOrder, bool, error) {
+	s, err := flags.GetString("sort")
+	if err != nil {
+		return "", false, fmt.Errorf("error reading sort flag: %w", err)
+	}
+	order := sozzler.ByName
+	if searching {
+		order = sozzler.ByMatch
+	}
+	if s != "" {
+		if order, err = sozzler.ParseSortOrder(s); err != nil {
+			return "", false, err
+		}
+	}
+	reverse, err := flags.GetBool("reverse")
+	if err != nil {
+		return "", false, fmt.Errorf("error reading reverse flag: %w", err)
+	}
+	return order, reverse, nil
+}
+
+// highlight styles the parts of m's Match that matched.
+func highlight(m sozzler.MatchResult) string {
+	style := lipgloss.NewStyle().Bold(true)
+	var b strings.Builder
+	last := 0
+	for _, h := range m.Highlights {
+		if h.Start < last || h.End > len(m.Match) || h.Start > h.End {
+			// overlaps the last one, or isn't in the match
+			continue
+		}
+		b.WriteString(m.Match[last:h.Start])
+		b.WriteString(style.Render(m.Match[h.Start:h.End]))
+		last = h.End
+	}
+	b.WriteString(m.Match[last:])
+	return b.String()
+}
+
+func makePredicates(flags *pflag.FlagSet, catalog *sozzler.RecipeCatalog) ([]sozzler.Predicate, error) {
 	var predicates []sozzler.Predicate
 
 	ingredients, err := flags.GetStringSlice("ingredients")
@@ -88,6 +196,30 @@ func makePredicates(flags *pflag.FlagSet) ([]sozzler.Predicate, error) {
 		predicates = append(predicates, sozzler.NewRatingPredicate(rating))
 	}
 
+	query, err := flags.GetString("query")
+	if err != nil {
+		return nil, fmt.Errorf("error reading query flag: %w", err)
+	}
+	if query != "" {
+		p, err := sozzler.ParseQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+
+	text, err := flags.GetString("text")
+	if err != nil {
+		return nil, fmt.Errorf("error reading text flag: %w", err)
+	}
+	if text != "" {
+		p, err := sozzler.NewNotesPredicate(sozzler.NewTextIndex(catalog.Recipes), text)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+
 	return predicates, nil
 }
 
@@ -97,4 +229,9 @@ func init() {
 	listCmd.Flags().StringSliceP("ingredients", "i", []string{}, "return recipes with ingredients, comma separated")
 	listCmd.Flags().StringSliceP("names", "n", []string{}, "return recipes by name, comma separated")
 	listCmd.Flags().IntP("rating", "r", 0, "return recipes with this rating or higher")
+	listCmd.Flags().String("sort", "", "sort by name, rating, match, ingredients, or added (default match when searching, name otherwise)")
+	listCmd.Flags().Bool("reverse", false, "reverse the sort order")
+	listCmd.Flags().String("text", "", `return recipes with every word in their notes; quote phrases, like 'chocolate "coupe glass"'`)
+	listCmd.Flags().StringP("query", "q", "", `return recipes matching a query, like 'ingredient:gin and not ingredient:egg and rating>=4'`)
+	addExcludeFlags(listCmd)
 }
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"mp/sozzler/pkg/display"
+	"mp/sozzler/pkg/sozzler"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var makeableCmd = &cobra.Command{
+	Use:   "makeable",
+	Short: "List the recipes you can make from your inventory",
+	Long: `List the recipes you can make with the ingredients in your inventory, best
+rated first.
+
+The inventory is a YAML list of ingredients, one per line, optionally with an
+amount:
+
+  - London Dry Gin
+  - 750 ml Campari
+  - Sweet Vermouth
+  - ingredient: Lime Juice
+    quantity: 0
+
+An ingredient with a quantity of 0 has run out. Owning a kind of an ingredient
+counts, so London dry gin satisfies a recipe asking for gin. Garnishes are
+optional, and water and ice are always on hand.
+
+Recipes are left out as they are by list, with --exclude-ingredients,
+--exclude-allergen, or the exclusions file.`,
+
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		catalog := cmd.Context().Value(catalogKey{}).(*sozzler.RecipeCatalog)
+		display := cmd.Context().Value(displayKey{}).(display.Display)
+
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		inventory, err := loadInventory(cmd.Flags(), true)
+		if err != nil {
+			return err
+		}
+
+		catalog, err = excludeRecipes(cmd.Flags(), catalog)
+		if err != nil {
+			return err
+		}
+
+		results := catalog.Search([]sozzler.Predicate{sozzler.NewMakeablePredicate(inventory, catalog.Ingredients)})
+		if len(results) == 0 {
+			display.String("nothing to make\n")
+			return nil
+		}
+		sozzler.SortResults(results, sozzler.ByRating, false)
+
+		if !verbose {
+			var recipes []*sozzler.Recipe
+			for _, result := range results {
+				recipes = append(recipes, result.Recipe)
+			}
+			display.List(recipes)
+			return nil
+		}
+		for _, result := range results {
+			display.Show(result.Recipe)
+			if match := result.Matches[0].Match; strings.HasPrefix(match, "without ") {
+				display.String("(" + match + ")\n")
+			}
+			display.String("\n")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(makeableCmd)
+	addInventoryFlag(makeableCmd)
+	addExcludeFlags(makeableCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"mp/sozzler/pkg/display"
+	"mp/sozzler/pkg/sozzler"
+
+	"github.com/spf13/cobra"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Create a recipe interactively and save it",
+	Long: `Create a recipe by answering prompts for its name, components, notes, and
+rating, then save it to the recipe directory. Each component is shown as it was
+understood before it's added. With --tui, the prompts are a form.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		catalog := cmd.Context().Value(catalogKey{}).(*sozzler.RecipeCatalog)
+		display := cmd.Context().Value(displayKey{}).(display.Display)
+
+		recipe, err := display.NewRecipe(catalog)
+		if err != nil {
+			return err
+		}
+		if recipe == nil {
+			display.String("canceled\n")
+			return nil
+		}
+
+		if err := catalog.Save(recipe); err != nil {
+			return err
+		}
+
+		display.String("saved " + recipe.Path + "\n")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"mp/sozzler/pkg/display"
 	"mp/sozzler/pkg/sozzler"
@@ -19,26 +20,50 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	var (
-		color bool
-		plain bool
-		tui   bool
+		color  bool
+		plain  bool
+		strict bool
+		tui    bool
 	)
 	rootCmd.PersistentFlags().BoolVarP(&color, "color", "c", false, "Force color mode when piping")
 	rootCmd.PersistentFlags().BoolVarP(&plain, "plain", "p", false, "Plain Text")
+	rootCmd.PersistentFlags().BoolVar(&strict, "strict", false, "Fail on the first recipe that can't be loaded")
 	rootCmd.PersistentFlags().BoolVarP(&tui, "tui", "t", false, "Terminal User Interface")
+	rootCmd.PersistentFlags().String("units", "", "show quantities in metric or imperial units")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringSlice("recipes-dir", []string{}, "recipe directory, may be repeated (default $"+recipesEnv+", then $XDG_DATA_HOME/sozzler/recipes, then ./recipes)")
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		var catalog sozzler.RecipeCatalog
-		if err := catalog.Load("./recipes"); err != nil {
+		dirs, err := recipeDirs(cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		catalog := sozzler.RecipeCatalog{Lenient: !strict}
+		if err := catalog.Load(dirs...); err != nil {
+			var loadErrs sozzler.LoadErrors
+			if strict || !errors.As(err, &loadErrs) {
+				return err
+			}
+			for _, e := range loadErrs {
+				fmt.Fprintln(os.Stderr, "warning:", e)
+			}
+		}
+
+		units, _ := cmd.Flags().GetString("units")
+		system, err := sozzler.ParseSystem(units)
+		if err != nil {
 			return err
 		}
 
 		var d display.Display = &display.StdoutDisplay{
 			Plain: plain,
+			Units: system,
 		}
 		if tui {
-			d = &display.TuiDisplay{}
+			d = &display.TuiDisplay{
+				Units: system,
+			}
 		}
 
 		if color {
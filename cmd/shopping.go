@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"mp/sozzler/pkg/display"
+	"mp/sozzler/pkg/sozzler"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var shoppingCmd = &cobra.Command{
+	Use:   "shopping <recipe name>... | --suggest[=n]",
+	Short: "Make a shopping list, or suggest what to buy for your bar",
+	Long: `Make a shopping list for a menu of recipes, like
+
+  sozzler shopping "Mai Tai" "Daiquiri" --servings 12
+
+The same ingredient is added up across recipes, what's in your inventory is
+taken off, and the list is grouped by where things are found in a store, with
+how many bottles or fruit to buy.
+
+With --suggest, instead suggest the ingredients to buy, one at a time, that
+make the most new recipes makeable from your inventory, weighted by rating.
+Each suggestion assumes you bought the ones before it.`,
+
+	SilenceUsage: true,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		catalog := cmd.Context().Value(catalogKey{}).(*sozzler.RecipeCatalog)
+		display := cmd.Context().Value(displayKey{}).(display.Display)
+
+		if cmd.Flags().Changed("suggest") {
+			if len(args) > 0 {
+				return fmt.Errorf("use recipe names or --suggest, not both")
+			}
+			return suggest(cmd, catalog, display)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("name the recipes to shop for, or use --suggest")
+		}
+
+		var recipes []*sozzler.Recipe
+		for _, name := range args {
+			recipe, err := catalog.Lookup(name)
+			if err != nil {
+				return err
+			}
+			recipes = append(recipes, recipe)
+		}
+
+		servings, err := cmd.Flags().GetInt("servings")
+		if err != nil {
+			return fmt.Errorf("error reading servings flag: %w", err)
+		}
+		if cmd.Flags().Changed("servings") && servings <= 0 {
+			return fmt.Errorf("invalid servings %d: use a positive number", servings)
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return fmt.Errorf("error reading format flag: %w", err)
+		}
+
+		inventory, err := loadInventory(cmd.Flags(), false)
+		if err != nil {
+			return err
+		}
+
+		items, err := sozzler.ShoppingList(recipes, sozzler.ShoppingOptions{
+			Servings:    servings,
+			Inventory:   inventory,
+			Ingredients: catalog.Ingredients,
+		})
+		if err != nil {
+			return err
+		}
+
+		units, _ := cmd.Flags().GetString("units")
+		out, err := describeShopping(items, sozzler.System(units), format)
+		if err != nil {
+			return err
+		}
+		display.String(out)
+		return nil
+	},
+}
+
+// suggest prints the ingredients to buy to make the most new recipes.
+func suggest(cmd *cobra.Command, catalog *sozzler.RecipeCatalog, display display.Display) error {
+	n, err := cmd.Flags().GetInt("suggest")
+	if err != nil {
+		return fmt.Errorf("error reading suggest flag: %w", err)
+	}
+	if n <= 0 {
+		return fmt.Errorf("invalid suggest %d: use a positive number", n)
+	}
+
+	inventory, err := loadInventory(cmd.Flags(), true)
+	if err != nil {
+		return err
+	}
+
+	suggestions := inventory.Suggest(catalog.Ingredients, catalog.Recipes, n)
+	if len(suggestions) == 0 {
+		display.String("nothing to suggest\n")
+		return nil
+	}
+	display.String(describeSuggestions(suggestions))
+	return nil
+}
+
+// describeSuggestions numbers the suggestions, with the recipes each unlocks.
+func describeSuggestions(suggestions []sozzler.Suggestion) string {
+	var b strings.Builder
+	for i, s := range suggestions {
+		fmt.Fprintf(&b, "%d. %s", i+1, s.Ingredient)
+		if len(s.Recipes) == 0 {
+			b.WriteString(": with the next\n")
+			continue
+		}
+		var names []string
+		for _, r := range s.Recipes {
+			names = append(names, r.Name)
+		}
+		noun := "recipes"
+		if len(names) == 1 {
+			noun = "recipe"
+		}
+		fmt.Fprintf(&b, ": %d more %s (%s)\n", len(names), noun, strings.Join(names, ", "))
+	}
+	return b.String()
+}
+
+// shoppingJSON is a shopping list item as JSON.
+type shoppingJSON struct {
+	Group      string   `json:"group"`
+	Ingredient string   `json:"ingredient"`
+	Quantity   string   `json:"quantity,omitempty"`
+	Amount     float64  `json:"amount,omitempty"`
+	Unit       string   `json:"unit,omitempty"`
+	Bottles    int      `json:"bottles,omitempty"`
+	BottleSize float64  `json:"bottle_ml,omitempty"`
+	Fruit      int      `json:"fruit,omitempty"`
+	Source     string   `json:"source,omitempty"`
+	Drinks     int      `json:"drinks"`
+	Recipes    []string `json:"recipes"`
+}
+
+// describeShopping formats a shopping list as text, markdown, or json, with
+// amounts in units.
+func describeShopping(items []sozzler.ShoppingItem, units sozzler.System, format string) (string, error) {
+	for i, item := range items {
+		c := sozzler.InSystem([]sozzler.Component{{Quantity: item.Quantity, Unit: item.Unit}}, units)[0]
+		items[i].Quantity, items[i].Unit = c.Quantity, c.Unit
+	}
+
+	var b strings.Builder
+	switch format {
+	case "text", "markdown":
+		if len(items) == 0 {
+			return "nothing to buy\n", nil
+		}
+		for i, item := range items {
+			if i == 0 || item.Group != items[i-1].Group {
+				if format == "markdown" {
+					if i > 0 {
+						b.WriteString("\n")
+					}
+					fmt.Fprintf(&b, "## %s\n\n", item.Group)
+				} else {
+					fmt.Fprintf(&b, "%s\n", item.Group)
+				}
+			}
+			prefix := "  "
+			if format == "markdown" {
+				prefix = "- [ ] "
+			}
+			b.WriteString(prefix + describeShoppingItem(item) + "\n")
+		}
+	case "json":
+		out := []shoppingJSON{}
+		for _, item := range items {
+			out = append(out, shoppingJSON{
+				Group:      item.Group,
+				Ingredient: item.Ingredient,
+				Quantity:   item.Quantity.String(),
+				Amount:     item.Quantity.Float(),
+				Unit:       item.Unit,
+				Bottles:    item.Bottles,
+				BottleSize: item.BottleSize,
+				Fruit:      item.Fruit,
+				Source:     item.Source,
+				Drinks:     item.Drinks,
+				Recipes:    item.Recipes,
+			})
+		}
+		encoded, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		b.Write(encoded)
+		b.WriteString("\n")
+	default:
+		return "", fmt.Errorf("unknown format %q: use text, markdown, or json", format)
+	}
+	return b.String(), nil
+}
+
+// describeShoppingItem describes an item like "12 oz Light Rum (1 bottle)".
+func describeShoppingItem(item sozzler.ShoppingItem) string {
+	s := strings.Join(strings.Fields(fmt.Sprint(item.Quantity, " ", item.Unit, " ", item.Ingredient)), " ")
+	switch {
+	case item.Bottles > 0:
+		s += fmt.Sprintf(" (%s)", plural(item.Bottles, "bottle"))
+	case item.Fruit > 0:
+		s += fmt.Sprintf(" (%s)", plural(item.Fruit, item.Source))
+	case item.Quantity.IsZero():
+		s += fmt.Sprintf(" (for %s)", plural(item.Drinks, "drink"))
+	}
+	return s
+}
+
+// plural formats n things, like "1 lime" or "3 limes".
+func plural(n int, thing string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, thing)
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
+
+func init() {
+	rootCmd.AddCommand(shoppingCmd)
+	addInventoryFlag(shoppingCmd)
+	shoppingCmd.Flags().Int("servings", 0, "shop for this many drinks of each recipe")
+	shoppingCmd.Flags().String("format", "text", "output format: text, markdown, or json")
+	shoppingCmd.Flags().Int("suggest", 5, "suggest up to this many ingredients to buy")
+	shoppingCmd.Flags().Lookup("suggest").NoOptDefVal = "5"
+}
@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"mp/sozzler/pkg/display"
 	"mp/sozzler/pkg/sozzler"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var showCmd = &cobra.Command{
@@ -19,22 +23,87 @@ var showCmd = &cobra.Command{
 		display := cmd.Context().Value(displayKey{}).(display.Display)
 
 		name := args[0]
-		recipe, ok := catalog.Find(name)
-		if !ok {
-			display.Error(fmt.Sprintf("couldn't find recipe %q", name))
+		recipe, err := catalog.Lookup(name)
+		if err != nil {
+			display.Error(err.Error())
 			return
 		}
 
-		scale, _ := cmd.Flags().GetInt("scale")
-		for idx := range recipe.Components {
-			recipe.Components[idx].Quantity = recipe.Components[idx].Quantity.Scale(scale)
+		factor, err := scaleFactor(cmd.Flags(), recipe)
+		if err != nil {
+			display.Error(err.Error())
+			return
+		}
+
+		scaled, err := recipe.Scale(factor)
+		if err != nil {
+			display.Error(err.Error())
+			return
 		}
+		display.Show(scaled)
 
-		display.Show(recipe)
+		if analyze, _ := cmd.Flags().GetBool("analyze"); analyze {
+			units, _ := cmd.Flags().GetString("units")
+			display.String(describeAnalysis(catalog.Ingredients.Analyze(recipe), recipe, sozzler.System(units)))
+		}
 	},
 }
 
+// describeAnalysis spells out an analysis of recipe, with volumes in units.
+func describeAnalysis(a sozzler.Analysis, recipe *sozzler.Recipe, units sozzler.System) string {
+	ml := sozzler.NewQuantity(int64(math.Round(a.Volume)), 1)
+	volume, unit := fmt.Sprintf("%.0f", ml.Float()), "ml"
+	if units == sozzler.Imperial {
+		if oz, err := sozzler.Convert(ml, "ml", "oz"); err == nil {
+			volume, unit = fmt.Sprintf("%.1f", oz.Float()), "oz"
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nEstimated per drink: %s %s, %.0f%% ABV", volume, unit, a.ABV)
+	switch {
+	case a.Dilution > 0:
+		fmt.Fprintf(&b, ", %.0f%% dilution (%s)", 100*a.Dilution, recipe.Method)
+	case recipe.Method == "":
+		b.WriteString(", no dilution (recipe has no method)")
+	default:
+		fmt.Fprintf(&b, ", no dilution (%s)", recipe.Method)
+	}
+	b.WriteString("\n")
+	if len(a.Unknown) > 0 {
+		fmt.Fprintf(&b, "Unknown ingredients, counted as 0%% ABV: %s\n", strings.Join(a.Unknown, ", "))
+	}
+	return b.String()
+}
+
+// scaleFactor reads --scale and --servings into the factor to scale recipe by.
+func scaleFactor(flags *pflag.FlagSet, recipe *sozzler.Recipe) (*big.Rat, error) {
+	scale, err := flags.GetString("scale")
+	if err != nil {
+		return nil, fmt.Errorf("error reading scale flag: %w", err)
+	}
+	servings, err := flags.GetInt("servings")
+	if err != nil {
+		return nil, fmt.Errorf("error reading servings flag: %w", err)
+	}
+
+	if flags.Changed("scale") && flags.Changed("servings") {
+		return nil, fmt.Errorf("use --scale or --servings, not both")
+	}
+
+	if flags.Changed("servings") {
+		if servings <= 0 {
+			return nil, fmt.Errorf("invalid servings %d: use a positive number", servings)
+		}
+		return big.NewRat(int64(servings), int64(recipe.Yield())), nil
+	}
+
+	return sozzler.ParseFactor(scale)
+}
+
 func init() {
 	rootCmd.AddCommand(showCmd)
-	showCmd.Flags().IntP("scale", "s", 1, "scale recipe")
+	showCmd.Flags().StringP("scale", "s", "1", "scale recipe by a number like 2, 1/2, or 1.5")
+	showCmd.Flags().Int("servings", 1, "scale recipe to make this many drinks")
+	showCmd.Flags().BoolP("analyze", "a", false, "estimate each drink's volume, ABV, and dilution")
 }
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"mp/sozzler/pkg/sozzler"
+
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the recipe library for problems",
+	Long: `Check every file in the recipe directories against a set of lint rules and
+report problems by file. Exits non-zero if any problem is an error.
+
+Rules:
+` + lintRulesHelp(),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+
+	// validate reports broken recipes itself, so it doesn't load the catalog.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("unknown format %q: use text or json", format)
+		}
+
+		dirs, err := recipeDirs(cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		diagnostics, err := sozzler.Lint(dirs...)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if format == "json" {
+			if diagnostics == nil {
+				diagnostics = []sozzler.Diagnostic{}
+			}
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(diagnostics); err != nil {
+				return fmt.Errorf("encoding JSON: %w", err)
+			}
+		} else {
+			for _, d := range diagnostics {
+				_, _ = fmt.Fprintln(out, d)
+			}
+		}
+
+		errors := 0
+		for _, d := range diagnostics {
+			if d.Severity == sozzler.SeverityError {
+				errors++
+			}
+		}
+		if errors > 0 {
+			return fmt.Errorf("%d errors, %d warnings", errors, len(diagnostics)-errors)
+		}
+		return nil
+	},
+}
+
+func lintRulesHelp() string {
+	var help string
+	for _, r := range sozzler.LintRules {
+		help += fmt.Sprintf("  %-22s %-8s %s\n", r.Name, r.Severity, r.Description)
+	}
+	return help
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringP("format", "f", "text", "output format: text or json")
+}
@@ -7,6 +7,7 @@ import (
 type Display interface {
 	Error(string)
 	List([]*sozzler.Recipe)
+	NewRecipe(*sozzler.RecipeCatalog) (*sozzler.Recipe, error)
 	Show(*sozzler.Recipe)
 	String(string)
 }
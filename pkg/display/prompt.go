@@ -0,0 +1,141 @@
+package display
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mp/sozzler/pkg/sozzler"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var errInputEnded = errors.New("input ended")
+
+// NewRecipe asks for a recipe one line at a time, so it works on any terminal.
+// It returns nil if input ends before the recipe is complete.
+func (d *StdoutDisplay) NewRecipe(catalog *sozzler.RecipeCatalog) (*sozzler.Recipe, error) {
+	recipe, err := promptRecipe(bufio.NewReader(os.Stdin), catalog)
+	if errors.Is(err, errInputEnded) {
+		return nil, nil
+	}
+	return recipe, err
+}
+
+func promptRecipe(in *bufio.Reader, catalog *sozzler.RecipeCatalog) (*sozzler.Recipe, error) {
+	var recipe sozzler.Recipe
+
+	for recipe.Name == "" {
+		name, err := readLine(in, "Name: ")
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := catalog.Find(name); ok {
+			fmt.Printf("there's already a recipe called %q\n", name)
+			continue
+		}
+		recipe.Name = name
+	}
+
+	fmt.Println("Components, one per line, like \"3/4 oz lime juice\". Blank line when done.")
+	parser := sozzler.RecipeParser{}
+	for {
+		line, err := readLine(in, "> ")
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			if len(recipe.Components) == 0 {
+				fmt.Println("a recipe needs at least one component")
+				continue
+			}
+			break
+		}
+
+		c, err := parser.ParseComponent(strings.NewReader(line))
+		if err != nil {
+			fmt.Printf("couldn't understand %q\n", line)
+			continue
+		}
+
+		ok, err := readLine(in, "  "+describeComponent(c)+" ok? [Y/n] ")
+		if err != nil {
+			return nil, err
+		}
+		if ok == "" || strings.HasPrefix(strings.ToLower(ok), "y") {
+			recipe.Components = append(recipe.Components, *c)
+		}
+	}
+
+	fmt.Println("Notes. Blank line when done.")
+	var notes []string
+	for {
+		line, err := readLine(in, "> ")
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			break
+		}
+		notes = append(notes, line)
+	}
+	recipe.Notes = strings.Join(notes, "\n")
+
+	for {
+		line, err := readLine(in, "Rating (0-5) [0]: ")
+		if err != nil {
+			return nil, err
+		}
+		rating, ok := parseRating(line)
+		if !ok {
+			fmt.Println("rating must be a number from 0 to 5")
+			continue
+		}
+		recipe.Rating = rating
+		break
+	}
+
+	return &recipe, nil
+}
+
+// readLine prints prompt and reads a trimmed line, or returns errInputEnded.
+func readLine(in *bufio.Reader, prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err == io.EOF {
+		fmt.Println()
+		return "", errInputEnded
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// describeComponent spells out how a component was understood.
+func describeComponent(c *sozzler.Component) string {
+	var parts []string
+	if q := c.Quantity.String(); q != "" {
+		parts = append(parts, "quantity "+q)
+	}
+	if c.Unit != "" {
+		parts = append(parts, "unit "+c.Unit)
+	}
+	parts = append(parts, "ingredient "+c.Ingredient)
+	return strings.Join(parts, ", ") + "."
+}
+
+func parseRating(s string) (int, bool) {
+	if s == "" {
+		return 0, true
+	}
+	rating, err := strconv.Atoi(s)
+	if err != nil || rating < 0 || rating > 5 {
+		return 0, false
+	}
+	return rating, true
+}
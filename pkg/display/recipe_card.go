@@ -10,6 +10,7 @@ import (
 
 type recipeCard struct {
 	recipe *sozzler.Recipe
+	units  sozzler.System
 	style  lipgloss.Style
 }
 
@@ -47,9 +48,11 @@ func (rc *recipeCard) Ingredients() string {
 	ingStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.AdaptiveColor{Light: "#111827", Dark: "#E5E7EB"})
 
+	components := sozzler.FancyOrder(sozzler.InSystem(rc.recipe.Components, rc.units))
+
 	// Align columns based on actual content widths (still fine for auto-fit).
 	qtyW, unitW := 0, 0
-	for _, c := range rc.recipe.Components {
+	for _, c := range components {
 		if x := lipgloss.Width(fmt.Sprint(c.Quantity)); x > qtyW {
 			qtyW = x
 		}
@@ -61,7 +64,7 @@ func (rc *recipeCard) Ingredients() string {
 	colUnit := unitStyle.Width(unitW)
 
 	var rows []string
-	for _, c := range sozzler.FancyOrder(rc.recipe.Components) {
+	for _, c := range components {
 		q := colQty.Render(fmt.Sprint(c.Quantity))
 		u := colUnit.Render(strings.TrimSpace(fmt.Sprint(c.Unit)))
 		ing := ingStyle.Render(fmt.Sprint(c.Ingredient))
@@ -71,9 +74,10 @@ func (rc *recipeCard) Ingredients() string {
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
-func renderRecipeCard(recipe *sozzler.Recipe) string {
+func renderRecipeCard(recipe *sozzler.Recipe, units sozzler.System) string {
 	rc := &recipeCard{
 		recipe: recipe,
+		units:  units,
 		style: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.AdaptiveColor{Light: "#C084FC", Dark: "#7C3AED"}).
@@ -7,6 +7,7 @@ import (
 
 type StdoutDisplay struct {
 	Plain bool
+	Units sozzler.System
 }
 
 func (d *StdoutDisplay) Error(e string) {
@@ -21,7 +22,7 @@ func (d *StdoutDisplay) List(recipes []*sozzler.Recipe) {
 
 func (d *StdoutDisplay) Show(recipe *sozzler.Recipe) {
 	d.printName(recipe)
-	for _, c := range sozzler.FancyOrder(recipe.Components) {
+	for _, c := range sozzler.FancyOrder(sozzler.InSystem(recipe.Components, d.Units)) {
 		if d.Plain {
 			fmt.Println(c.Quantity, c.Unit, c.Ingredient)
 		} else {
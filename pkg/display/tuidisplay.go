@@ -12,7 +12,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-type TuiDisplay struct{}
+type TuiDisplay struct {
+	Units sozzler.System
+}
 
 func (d *TuiDisplay) Error(e string) {
 	fmt.Println(e)
@@ -78,6 +80,7 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 
 type model struct {
 	list     list.Model
+	units    sozzler.System
 	choice   *sozzler.Recipe
 	screen   screen
 	width    int
@@ -136,7 +139,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m model) View() string {
 	if m.screen == screenDetail && m.choice != nil {
-		card := renderRecipeCard(m.choice)
+		card := renderRecipeCard(m.choice, m.units)
 		hint := lipgloss.NewStyle().Faint(true).MarginTop(1).PaddingLeft(2).
 			Render("esc/backspace to go back · q to quit")
 		return lipgloss.JoinVertical(lipgloss.Left, card, hint)
@@ -176,7 +179,7 @@ func (d *TuiDisplay) List(recipes []*sozzler.Recipe) {
 
 	l.KeyMap = km
 
-	m := model{list: l, screen: screenList}
+	m := model{list: l, units: d.Units, screen: screenList}
 
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
@@ -188,5 +191,5 @@ func (d *TuiDisplay) List(recipes []*sozzler.Recipe) {
 // Show remains useful for non-interactive output (e.g., piping)
 // It reuses the same renderer as the interactive detail screen.
 func (d *TuiDisplay) Show(recipe *sozzler.Recipe) {
-	fmt.Println(renderRecipeCard(recipe))
+	fmt.Println(renderRecipeCard(recipe, d.Units))
 }
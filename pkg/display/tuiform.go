@@ -0,0 +1,251 @@
+package display
+
+import (
+	"fmt"
+	"mp/sozzler/pkg/sozzler"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// -----------------------------------------------------------------------------
+// Steps
+// -----------------------------------------------------------------------------
+
+type formStep int
+
+const (
+	stepName formStep = iota
+	stepComponents
+	stepNotes
+	stepRating
+	stepConfirm
+)
+
+// -----------------------------------------------------------------------------
+// Styles for the new recipe form
+// -----------------------------------------------------------------------------
+
+var (
+	formLabelStyle   = lipgloss.NewStyle().Bold(true).MarginLeft(2)
+	formBodyStyle    = lipgloss.NewStyle().MarginLeft(4)
+	formPreviewStyle = lipgloss.NewStyle().Faint(true).MarginLeft(4)
+	formErrorStyle   = lipgloss.NewStyle().MarginLeft(4).Foreground(lipgloss.Color("9"))
+	formHintStyle    = lipgloss.NewStyle().Faint(true).MarginTop(1).PaddingLeft(2)
+)
+
+// -----------------------------------------------------------------------------
+// Model
+// -----------------------------------------------------------------------------
+
+type formModel struct {
+	catalog  *sozzler.RecipeCatalog
+	parser   sozzler.RecipeParser
+	step     formStep
+	input    textinput.Model
+	notes    textarea.Model
+	recipe   sozzler.Recipe
+	err      string
+	saved    bool
+	quitting bool
+}
+
+func newFormModel(catalog *sozzler.RecipeCatalog) formModel {
+	input := textinput.New()
+	input.Placeholder = "Banana Fabrication"
+	input.Focus()
+
+	notes := textarea.New()
+	notes.Placeholder = "Shake with ice, strain into a chilled coupe."
+	notes.ShowLineNumbers = false
+
+	return formModel{catalog: catalog, input: input, notes: notes}
+}
+
+func (m formModel) Init() tea.Cmd { return textinput.Blink }
+
+func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		m.notes.SetWidth(min(msg.Width-6, 80))
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+		m.err = ""
+		switch m.step {
+		case stepName:
+			if msg.String() == "enter" {
+				return m.enterName()
+			}
+		case stepComponents:
+			switch msg.String() {
+			case "enter":
+				return m.enterComponent()
+			case "ctrl+z":
+				if n := len(m.recipe.Components); n > 0 {
+					m.recipe.Components = m.recipe.Components[:n-1]
+				}
+				return m, nil
+			}
+		case stepNotes:
+			if msg.String() == "ctrl+d" {
+				m.recipe.Notes = strings.TrimSpace(m.notes.Value())
+				m.notes.Blur()
+				m.step = stepRating
+				m.input.Reset()
+				m.input.Placeholder = "0"
+				return m, m.input.Focus()
+			}
+		case stepRating:
+			if msg.String() == "enter" {
+				rating, ok := parseRating(strings.TrimSpace(m.input.Value()))
+				if !ok {
+					m.err = "rating must be a number from 0 to 5"
+					return m, nil
+				}
+				m.recipe.Rating = rating
+				m.input.Blur()
+				m.step = stepConfirm
+				return m, nil
+			}
+		case stepConfirm:
+			switch msg.String() {
+			case "y", "enter":
+				m.saved = true
+				return m, tea.Quit
+			case "n":
+				m.quitting = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.step == stepNotes {
+		m.notes, cmd = m.notes.Update(msg)
+	} else {
+		m.input, cmd = m.input.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m formModel) enterName() (tea.Model, tea.Cmd) {
+	name := strings.TrimSpace(m.input.Value())
+	if name == "" {
+		m.err = "a recipe needs a name"
+		return m, nil
+	}
+	if _, ok := m.catalog.Find(name); ok {
+		m.err = fmt.Sprintf("there's already a recipe called %q", name)
+		return m, nil
+	}
+	m.recipe.Name = name
+	m.step = stepComponents
+	m.input.Reset()
+	m.input.Placeholder = "3/4 oz lime juice"
+	return m, nil
+}
+
+func (m formModel) enterComponent() (tea.Model, tea.Cmd) {
+	line := strings.TrimSpace(m.input.Value())
+	if line == "" {
+		if len(m.recipe.Components) == 0 {
+			m.err = "a recipe needs at least one component"
+			return m, nil
+		}
+		m.input.Blur()
+		m.step = stepNotes
+		return m, m.notes.Focus()
+	}
+
+	c, err := m.parser.ParseComponent(strings.NewReader(line))
+	if err != nil {
+		m.err = fmt.Sprintf("couldn't understand %q", line)
+		return m, nil
+	}
+	m.recipe.Components = append(m.recipe.Components, *c)
+	m.input.Reset()
+	return m, nil
+}
+
+func (m formModel) View() string {
+	if m.quitting || m.saved {
+		return ""
+	}
+
+	if m.step == stepConfirm {
+		hint := formHintStyle.Render("y/enter to save · n/esc to cancel")
+		return lipgloss.JoinVertical(lipgloss.Left, renderRecipeCard(&m.recipe, ""), hint)
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + formLabelStyle.Render("🍸 New recipe") + "\n\n")
+
+	if m.step > stepName {
+		b.WriteString(formLabelStyle.Render("Name") + "\n" + formBodyStyle.Render(m.recipe.Name) + "\n")
+	}
+
+	var hint string
+	switch m.step {
+	case stepName:
+		b.WriteString(formLabelStyle.Render("Name") + "\n" + formBodyStyle.Render(m.input.View()) + "\n")
+		hint = "enter to continue · esc to cancel"
+	case stepComponents:
+		b.WriteString(formLabelStyle.Render("Components") + "\n")
+		for _, c := range m.recipe.Components {
+			b.WriteString(formBodyStyle.Render("• "+describeComponent(&c)) + "\n")
+		}
+		b.WriteString(formBodyStyle.Render(m.input.View()) + "\n")
+		if line := strings.TrimSpace(m.input.Value()); line != "" {
+			preview := "?"
+			if c, err := m.parser.ParseComponent(strings.NewReader(line)); err == nil {
+				preview = describeComponent(c)
+			}
+			b.WriteString(formPreviewStyle.Render("→ "+preview) + "\n")
+		}
+		hint = "enter to add · enter on an empty line when done · ctrl+z to remove the last · esc to cancel"
+	case stepNotes:
+		b.WriteString(formLabelStyle.Render("Notes") + "\n" + formBodyStyle.Render(m.notes.View()) + "\n")
+		hint = "ctrl+d when done · esc to cancel"
+	case stepRating:
+		b.WriteString(formLabelStyle.Render("Rating (0-5)") + "\n" + formBodyStyle.Render(m.input.View()) + "\n")
+		hint = "enter to continue · esc to cancel"
+	}
+
+	if m.err != "" {
+		b.WriteString(formErrorStyle.Render(m.err) + "\n")
+	}
+	b.WriteString(formHintStyle.Render(hint))
+	return b.String()
+}
+
+// -----------------------------------------------------------------------------
+// Public API
+// -----------------------------------------------------------------------------
+
+// NewRecipe shows a form for a new recipe. It returns nil if the form is
+// canceled.
+func (d *TuiDisplay) NewRecipe(catalog *sozzler.RecipeCatalog) (*sozzler.Recipe, error) {
+	p := tea.NewProgram(newFormModel(catalog))
+	final, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	m := final.(formModel)
+	if !m.saved {
+		return nil, nil
+	}
+	return &m.recipe, nil
+}
@@ -0,0 +1,91 @@
+package sozzler
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Method is how a drink is mixed, which decides how much it's diluted by ice.
+type Method string
+
+const (
+	Shaken  Method = "shaken"
+	Stirred Method = "stirred"
+	Built   Method = "built"
+)
+
+// Methods are the methods a recipe can have.
+var Methods = []Method{Shaken, Stirred, Built}
+
+func ParseMethod(s string) (Method, error) {
+	for _, m := range Methods {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown method %q: use shaken, stirred, or built", s)
+}
+
+// Analysis is an estimate of what's in a finished drink.
+type Analysis struct {
+	// Volume is the drink's volume in ml, including dilution.
+	Volume float64
+	// ABV is the drink's alcohol by volume in percent, after dilution.
+	ABV float64
+	// Dilution is the water melted from ice while mixing, as a fraction of the
+	// volume before mixing.
+	Dilution float64
+	// Unknown are ingredients measured by volume that aren't in the
+	// ingredient database, or have no ABV there. They're counted in Volume,
+	// but as if they had no alcohol.
+	Unknown []string
+}
+
+// Analyze estimates the final volume and ABV of one serving of r, with the
+// default ingredient database.
+func Analyze(r *Recipe) Analysis {
+	return defaultDB.Analyze(r)
+}
+
+// Analyze estimates the final volume and ABV of one serving of r. Dilution is
+// estimated from the ABV before mixing, with Dave Arnold's models for shaken
+// and stirred drinks. Built drinks, and drinks with no method, are taken to be
+// undiluted. Only components measured by volume are counted.
+func (db *IngredientDB) Analyze(r *Recipe) Analysis {
+	var a Analysis
+	var alcohol float64
+	unknown := make(map[string]bool)
+
+	for _, c := range r.Components {
+		ml, ok := c.Volume()
+		if !ok || c.Quantity.IsZero() {
+			continue
+		}
+		ml /= float64(r.Yield())
+
+		a.Volume += ml
+		if in, ok := db.Resolve(c.Ingredient); ok && in.ABV != nil {
+			alcohol += ml * *in.ABV / 100
+		} else if !unknown[c.Ingredient] {
+			unknown[c.Ingredient] = true
+			a.Unknown = append(a.Unknown, c.Ingredient)
+		}
+	}
+	sort.Strings(a.Unknown)
+
+	if a.Volume == 0 {
+		return a
+	}
+
+	abv := alcohol / a.Volume
+	switch r.Method {
+	case Shaken:
+		a.Dilution = -1.567*abv*abv + 1.742*abv + 0.203
+	case Stirred:
+		a.Dilution = -1.21*abv*abv + 1.246*abv + 0.145
+	}
+
+	a.Volume *= 1 + a.Dilution
+	a.ABV = 100 * alcohol / a.Volume
+	return a
+}
@@ -0,0 +1,55 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	daiquiri := func(method sozzler.Method) *sozzler.Recipe {
+		return &sozzler.Recipe{
+			Name:   "Daiquiri",
+			Method: method,
+			Components: []sozzler.Component{
+				*component("Light Rum", "2", "oz"),
+				*component("lime juice", "1", "oz"),
+				*component("simple syrup", "3/4", "oz"),
+				*component("lime wheel", "", ""),
+			},
+		}
+	}
+
+	built := sozzler.Analyze(daiquiri(sozzler.Built))
+	assert.InDelta(t, 110.9, built.Volume, 0.1)
+	assert.InDelta(t, 21.3, built.ABV, 0.1)
+	assert.Zero(t, built.Dilution)
+	assert.Empty(t, built.Unknown)
+
+	shaken := sozzler.Analyze(daiquiri(sozzler.Shaken))
+	assert.InDelta(t, 0.50, shaken.Dilution, 0.01)
+	assert.InDelta(t, built.Volume*(1+shaken.Dilution), shaken.Volume, 0.01)
+	assert.InDelta(t, 14.2, shaken.ABV, 0.1)
+
+	stirred := sozzler.Analyze(daiquiri(sozzler.Stirred))
+	assert.Less(t, stirred.Dilution, shaken.Dilution)
+}
+
+func TestAnalyzeUnknown(t *testing.T) {
+	recipe := &sozzler.Recipe{
+		Name:     "Punch",
+		Servings: 2,
+		Components: []sozzler.Component{
+			*component("gin", "4", "oz"),
+			*component("Mystery Cordial", "2", "oz"),
+			*component("Mystery Cordial", "1", "tsp"),
+			*component("egg", "1", ""),
+		},
+	}
+
+	a := sozzler.Analyze(recipe)
+	assert.Equal(t, []string{"Mystery Cordial"}, a.Unknown)
+	assert.InDelta(t, 91.2, a.Volume, 0.1, "one serving")
+	assert.InDelta(t, 25.9, a.ABV, 0.1)
+}
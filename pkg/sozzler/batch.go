@@ -0,0 +1,196 @@
+package sozzler
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DefaultUnbatchable are the ingredients that don't keep or don't survive
+// bottling, so they're added to each drink instead of the batch.
+var DefaultUnbatchable = []string{
+	"juice", "soda", "tonic", "ginger beer", "ginger ale", "champagne",
+	"prosecco", "sparkling wine", "egg", "egg white", "cream", "milk",
+	"garnish", "mint", "wheel", "wedge", "twist", "peel", "shell",
+}
+
+var ErrNothingToBatch = errors.New("nothing to batch")
+
+// BatchOptions say how big a batch to make, by Volume or by Servings.
+type BatchOptions struct {
+	// Volume is the size of the batch, including any dilution water, in
+	// VolumeUnit.
+	Volume     Quantity
+	VolumeUnit string
+	// Servings is how many drinks the batch makes, if Volume isn't given.
+	Servings int
+	// Dilution is how much water to add, as a fraction of the batch before
+	// dilution, like 1/5 for stirred drinks. Nil adds none.
+	Dilution *big.Rat
+	// Unbatchable are words for ingredients to leave out of the batch, like
+	// "juice". Unmeasured components, like garnishes, are always left out.
+	Unbatchable []string
+	// Ingredients, if set, leaves out Fresh ingredients too.
+	Ingredients *IngredientDB
+}
+
+// batchable reports whether c can go in the batch.
+func (opts BatchOptions) batchable(c Component) bool {
+	if !c.Batchable(opts.Unbatchable) {
+		return false
+	}
+	if opts.Ingredients == nil {
+		return true
+	}
+	in, ok := opts.Ingredients.Resolve(c.Ingredient)
+	return !ok || in.Perishability != Fresh
+}
+
+// Batch is a recipe scaled up to be mixed ahead of time.
+type Batch struct {
+	// Recipe has the batched components, and a water component if the batch
+	// is diluted.
+	Recipe *Recipe
+	// Servings is how many drinks the batch makes.
+	Servings *big.Rat
+	// Unbatched are the components left out of the batch, in the amounts to
+	// add to each drink.
+	Unbatched []Component
+}
+
+// Batchable reports whether c can be mixed ahead of time: it's measured, and
+// isn't one of the unbatchable ingredients.
+func (c Component) Batchable(unbatchable []string) bool {
+	if c.Quantity.IsZero() {
+		return false
+	}
+	words := " " + strings.ToLower(c.Ingredient) + " "
+	for _, u := range unbatchable {
+		u = strings.ToLower(strings.TrimSpace(u))
+		if u == "" {
+			continue
+		}
+		if strings.Contains(words, " "+u+" ") || strings.Contains(words, " "+u+"s ") {
+			return false
+		}
+	}
+	return true
+}
+
+// Batch scales r to a batch of opts.Volume or opts.Servings drinks, leaving out
+// the components that can't be batched. Amounts are rounded to what can be
+// measured.
+func (r *Recipe) Batch(opts BatchOptions) (*Batch, error) {
+	var batched, unbatched []Component
+	perServing := big.NewRat(1, int64(r.Yield()))
+	for _, c := range r.Components {
+		if opts.batchable(c) {
+			batched = append(batched, c)
+		} else {
+			q, err := c.Quantity.Scale(perServing)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't batch %q: %w", r.Name, err)
+			}
+			c.Quantity = q
+			unbatched = append(unbatched, c)
+		}
+	}
+	if len(batched) == 0 {
+		return nil, fmt.Errorf("couldn't batch %q: %w", r.Name, ErrNothingToBatch)
+	}
+
+	// volume is the ml of one recipe's batched components, before dilution
+	volume := new(big.Rat)
+	unit := ""
+	var largest *big.Rat
+	for _, c := range batched {
+		u, ok := LookupUnit(c.Unit)
+		if !ok || u.Dimension != Volume {
+			continue
+		}
+		ml := new(big.Rat).Mul(c.Quantity.Rat(), u.size)
+		volume.Add(volume, ml)
+		if largest == nil || ml.Cmp(largest) > 0 {
+			largest, unit = ml, u.Name
+		}
+	}
+
+	dilution := new(big.Rat)
+	if opts.Dilution != nil {
+		dilution.Set(opts.Dilution)
+	}
+	diluted := new(big.Rat).Add(big.NewRat(1, 1), dilution)
+
+	var factor *big.Rat
+	switch {
+	case !opts.Volume.IsZero():
+		u, ok := LookupUnit(opts.VolumeUnit)
+		if !ok || u.Dimension != Volume {
+			return nil, fmt.Errorf("couldn't batch %q: %q is not a unit of volume", r.Name, opts.VolumeUnit)
+		}
+		if volume.Sign() == 0 {
+			return nil, fmt.Errorf("couldn't batch %q by volume: %w measured by volume", r.Name, ErrNothingToBatch)
+		}
+		target := new(big.Rat).Mul(opts.Volume.Rat(), u.size)
+		factor = target.Quo(target, new(big.Rat).Mul(volume, diluted))
+	case opts.Servings > 0:
+		factor = big.NewRat(int64(opts.Servings), int64(r.Yield()))
+	default:
+		return nil, fmt.Errorf("couldn't batch %q: give a volume or number of servings", r.Name)
+	}
+
+	batch := &Recipe{
+		Name:       r.Name,
+		Rating:     r.Rating,
+		Method:     r.Method,
+		Components: batched,
+		Notes:      r.Notes,
+		Path:       r.Path,
+	}
+	batch, err := batch.Scale(factor)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't batch %q: %w", r.Name, err)
+	}
+
+	if dilution.Sign() > 0 && volume.Sign() > 0 {
+		water := new(big.Rat).Mul(volume, factor)
+		water.Mul(water, dilution)
+		q, err := quantity(water)
+		if err == nil {
+			q, err = Convert(q, "ml", unit)
+		}
+		if err != nil {
+			return nil, err
+		}
+		batch.Components = append(batch.Components, Component{
+			Ingredient: "water",
+			Quantity:   q.Measurable(),
+			Unit:       unit,
+		})
+	}
+
+	servings := new(big.Rat).Mul(factor, big.NewRat(int64(r.Yield()), 1))
+	if _, err := quantity(servings); err != nil {
+		return nil, fmt.Errorf("couldn't batch %q: %w servings", r.Name, err)
+	}
+	batch.Servings = 0
+	if servings.IsInt() {
+		batch.Servings = int(servings.Num().Int64())
+	}
+
+	return &Batch{Recipe: batch, Servings: servings, Unbatched: unbatched}, nil
+}
+
+// ParseMeasure parses an amount with a unit, like "750ml" or "1 1/2 l".
+func ParseMeasure(s string) (Quantity, *Unit, error) {
+	q, rest, err := quantityPrefix(s)
+	if err != nil || q.IsZero() || q.IsRange() {
+		return Quantity{}, nil, fmt.Errorf("invalid amount %q: use an amount and unit, like 750ml", s)
+	}
+	u, ok := LookupUnit(strings.TrimSpace(rest))
+	if !ok {
+		return Quantity{}, nil, fmt.Errorf("invalid amount %q: unknown unit %q", s, strings.TrimSpace(rest))
+	}
+	return q, u, nil
+}
@@ -0,0 +1,125 @@
+package sozzler_test
+
+import (
+	"math/big"
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	recipe := &sozzler.Recipe{
+		Name: "Daiquiri",
+		Components: []sozzler.Component{
+			*component("rum", "2", "oz"),
+			*component("lime juice", "1", "oz"),
+			*component("simple syrup", "1", "oz"),
+			*component("lime wheel", "", ""),
+		},
+	}
+
+	testCases := []struct {
+		desc         string
+		opts         sozzler.BatchOptions
+		want         []string
+		wantServings *big.Rat
+	}{
+		{
+			desc:         "servings",
+			opts:         sozzler.BatchOptions{Servings: 10},
+			want:         []string{"20 oz rum", "10 oz simple syrup"},
+			wantServings: big.NewRat(10, 1),
+		},
+		{
+			desc:         "volume",
+			opts:         sozzler.BatchOptions{Volume: sozzler.NewQuantity(24, 1), VolumeUnit: "oz"},
+			want:         []string{"16 oz rum", "8 oz simple syrup"},
+			wantServings: big.NewRat(8, 1),
+		},
+		{
+			desc: "volume with dilution",
+			opts: sozzler.BatchOptions{
+				Volume:     sozzler.NewQuantity(36, 1),
+				VolumeUnit: "oz",
+				Dilution:   big.NewRat(1, 2),
+			},
+			want:         []string{"16 oz rum", "8 oz simple syrup", "12 oz water"},
+			wantServings: big.NewRat(8, 1),
+		},
+		{
+			desc:         "nothing unbatchable",
+			opts:         sozzler.BatchOptions{Servings: 2, Unbatchable: []string{}},
+			want:         []string{"4 oz rum", "2 oz lime juice", "2 oz simple syrup"},
+			wantServings: big.NewRat(2, 1),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if tC.opts.Unbatchable == nil {
+				tC.opts.Unbatchable = sozzler.DefaultUnbatchable
+			}
+
+			batch, err := recipe.Batch(tC.opts)
+			require.NoError(t, err)
+
+			var got []string
+			for _, c := range batch.Recipe.Components {
+				got = append(got, c.Quantity.String()+" "+c.Unit+" "+c.Ingredient)
+			}
+			assert.Equal(t, tC.want, got)
+			assert.Equal(t, tC.wantServings, batch.Servings)
+		})
+	}
+}
+
+func TestBatchUnbatched(t *testing.T) {
+	recipe := &sozzler.Recipe{
+		Name:     "Punch",
+		Servings: 4,
+		Components: []sozzler.Component{
+			*component("rum", "8", "oz"),
+			*component("lemon juice", "4", "oz"),
+			*component("egg whites", "2", ""),
+		},
+	}
+
+	batch, err := recipe.Batch(sozzler.BatchOptions{Servings: 8, Unbatchable: sozzler.DefaultUnbatchable})
+	require.NoError(t, err)
+
+	assert.Equal(t, "16", batch.Recipe.Components[0].Quantity.String())
+	require.Len(t, batch.Unbatched, 2)
+	assert.Equal(t, "1", batch.Unbatched[0].Quantity.String(), "per drink")
+	assert.Equal(t, "1/2", batch.Unbatched[1].Quantity.String(), "per drink")
+}
+
+func TestBatchErrors(t *testing.T) {
+	juice := &sozzler.Recipe{Name: "Juice", Components: []sozzler.Component{*component("orange juice", "4", "oz")}}
+	_, err := juice.Batch(sozzler.BatchOptions{Servings: 2, Unbatchable: sozzler.DefaultUnbatchable})
+	assert.ErrorIs(t, err, sozzler.ErrNothingToBatch)
+
+	eggs := &sozzler.Recipe{Name: "Eggs", Components: []sozzler.Component{*component("eggnog", "2", "")}}
+	_, err = eggs.Batch(sozzler.BatchOptions{Volume: sozzler.NewQuantity(1, 1), VolumeUnit: "l"})
+	assert.ErrorIs(t, err, sozzler.ErrNothingToBatch)
+
+	_, err = eggs.Batch(sozzler.BatchOptions{Volume: sozzler.NewQuantity(1, 1), VolumeUnit: "g"})
+	assert.Error(t, err)
+}
+
+func TestParseMeasure(t *testing.T) {
+	q, u, err := sozzler.ParseMeasure("750ml")
+	require.NoError(t, err)
+	assert.Equal(t, "750", q.String())
+	assert.Equal(t, "ml", u.Name)
+
+	q, u, err = sozzler.ParseMeasure("1 1/2 liters")
+	require.NoError(t, err)
+	assert.Equal(t, "1 1/2", q.String())
+	assert.Equal(t, "l", u.Name)
+
+	for _, given := range []string{"", "750", "ml", "750 glugs", "1-2 l"} {
+		_, _, err := sozzler.ParseMeasure(given)
+		assert.Error(t, err, given)
+	}
+}
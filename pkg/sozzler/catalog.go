@@ -1,9 +1,12 @@
 package sozzler
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -11,69 +14,228 @@ import (
 
 type RecipeCatalog struct {
 	Recipes []*Recipe
+
+	// Dir is the directory Save writes recipes to. Load sets it to the last
+	// directory it loads, if it isn't already set.
+	Dir string
+
+	// Dirs are the directories Load has read recipes from, in order.
+	Dirs []string
+
+	// Lenient loads every valid recipe and reports broken ones together as
+	// LoadErrors, instead of stopping at the first broken recipe.
+	Lenient bool
+
+	// Ingredients resolves the ingredients of the catalog's recipes. Loading
+	// starts it with DefaultIngredients, if it isn't set, and adds the
+	// IngredientsFile of each directory loaded.
+	Ingredients *IngredientDB
 }
 
-func (rc *RecipeCatalog) Find(name string) (*Recipe, bool) {
-	name = strings.ToLower(name)
-	for _, r := range rc.Recipes {
-		if strings.ToLower(r.Name) == name {
-			return r, true
+// Load reads every recipe in recipesDirs into the catalog. Directories are
+// loaded in order, and a recipe loaded later replaces an earlier recipe with the
+// same name, so a personal overlay directory can be listed after a shared one.
+func (rc *RecipeCatalog) Load(recipesDirs ...string) error {
+	if rc.Dir == "" && len(recipesDirs) > 0 {
+		rc.Dir = recipesDirs[len(recipesDirs)-1]
+	}
+
+	var loadErrs LoadErrors
+	for _, dir := range recipesDirs {
+		rc.Dirs = append(rc.Dirs, dir)
+		if err := rc.loadDir(dir); err != nil {
+			var errs LoadErrors
+			if !rc.Lenient || !errors.As(err, &errs) {
+				return err
+			}
+			loadErrs = append(loadErrs, errs...)
 		}
 	}
-	return nil, false
+	if len(loadErrs) > 0 {
+		return loadErrs
+	}
+	return nil
 }
 
-func (rc *RecipeCatalog) Load(recipesDir string) error {
-	entries, err := os.ReadDir(recipesDir)
+func (rc *RecipeCatalog) loadDir(recipesDir string) error {
+	return rc.loadFS(os.DirFS(recipesDir), ".", recipesDir)
+}
+
+// LoadFS reads every recipe under root in fsys into the catalog, descending into
+// subdirectories. Hidden files and directories, such as .git, are skipped. As with Load, a
+// recipe replaces an already loaded recipe with the same name.
+func (rc *RecipeCatalog) LoadFS(fsys fs.FS, root string) error {
+	return rc.loadFS(fsys, root, "")
+}
+
+// loadFS walks root in fsys. Paths in errors are joined onto dir, so that
+// recipes loaded from disk are reported by their real location.
+func (rc *RecipeCatalog) loadFS(fsys fs.FS, root string, dir string) error {
+	var loadErrs LoadErrors
+
+	if rc.Ingredients == nil {
+		rc.Ingredients = DefaultIngredients()
+	}
+	if errs := loadIngredients(rc.Ingredients, fsys, root, dir); errs != nil {
+		if err := rc.fail(&loadErrs, errs); err != nil {
+			return err
+		}
+	}
+
+	err := walkRecipes(fsys, root, dir, func(rf *recipeFile) error {
+		if rf.errs != nil {
+			return rc.fail(&loadErrs, rf.errs)
+		}
+		rc.add(rf.recipe)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("couldn't list recipes in directory %q: %w", recipesDir, err)
+		return err
+	}
+	if len(loadErrs) > 0 {
+		return loadErrs
 	}
+	return nil
+}
+
+// recipeFile is one file found by walkRecipes. Exactly one of recipe and errs is
+// set.
+type recipeFile struct {
+	path   string
+	recipe *Recipe
+	doc    *yaml.Node
+	errs   LoadErrors
+}
 
-	for _, entry := range entries {
+// walkRecipes decodes every file under root in fsys, skipping hidden files and
+// directories and the IngredientsFile, and calls fn with each. Paths are joined
+// onto dir. It only fails on its own if root can't be listed.
+func walkRecipes(fsys fs.FS, root string, dir string, fn func(*recipeFile) error) error {
+	return fs.WalkDir(fsys, root, func(path string, entry fs.DirEntry, err error) error {
+		filename := filepath.Join(dir, filepath.FromSlash(path))
+		if err != nil {
+			if path == root {
+				return fmt.Errorf("couldn't list recipes in directory %q: %w", filename, err)
+			}
+			return fn(&recipeFile{path: filename, errs: LoadErrors{{Path: filename, Err: err}}})
+		}
+
+		hidden := path != root && strings.HasPrefix(entry.Name(), ".")
 		if entry.IsDir() {
-			continue
+			if hidden {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if hidden || isIngredientsFile(path, root) {
+			return nil
 		}
 
-		filename := filepath.Join(recipesDir, entry.Name())
-		file, err := os.Open(filename)
+		file, err := fsys.Open(path)
 		if err != nil {
-			return fmt.Errorf("couldn't open recipe file %q: %w", filename, err)
+			return fn(&recipeFile{path: filename, errs: LoadErrors{{Path: filename, Err: err}}})
 		}
 
-		var recipe Recipe
-		err = yaml.NewDecoder(file).Decode(&recipe)
+		recipe, doc, errs := decodeRecipe(filename, file)
 		_ = file.Close()
 
-		if err != nil {
-			return fmt.Errorf("error decoding recipe %q: %w", filename, err)
+		if recipe != nil {
+			recipe.Path = filename
+			if info, err := entry.Info(); err == nil {
+				recipe.Added = info.ModTime()
+			}
 		}
 
-		rc.Recipes = append(rc.Recipes, &recipe)
+		return fn(&recipeFile{path: filename, recipe: recipe, doc: doc, errs: errs})
+	})
+}
+
+// fail records errs, and returns them to stop the walk unless rc is lenient.
+func (rc *RecipeCatalog) fail(loadErrs *LoadErrors, errs LoadErrors) error {
+	if !rc.Lenient {
+		return errs
 	}
+	*loadErrs = append(*loadErrs, errs...)
 	return nil
 }
 
+func (rc *RecipeCatalog) add(recipe *Recipe) {
+	name := foldName(recipe.Name)
+	for i, r := range rc.Recipes {
+		if foldName(r.Name) == name {
+			rc.Recipes[i] = recipe
+			return
+		}
+	}
+	rc.Recipes = append(rc.Recipes, recipe)
+}
+
 type MatchResult struct {
 	Predicate Predicate
 	Match     string
+	// Score is how well it matched. See ScoredPredicate.
+	Score float64
+	// Highlights are the parts of Match that matched, for predicates that
+	// match an excerpt of longer text. See SnippetPredicate.
+	Highlights []TextSpan
+}
+
+// SearchResult is a recipe found by Search, and what it matched.
+type SearchResult struct {
+	Recipe  *Recipe
+	Matches []MatchResult
+	// Score is the sum of the scores of Matches, so recipes matching more
+	// predicates, and matching them better, score higher.
+	Score float64
+}
+
+// Search returns the recipes matching any of predicates, best scoring first,
+// then in catalog order. Use SortResults to put them in another order. If
+// nothing matches, it searches again with the FuzzyPredicates among
+// predicates allowing typos.
+func (rc *RecipeCatalog) Search(predicates []Predicate) []SearchResult {
+	if results := rc.search(predicates); len(results) > 0 {
+		return results
+	}
+
+	fuzzy := make([]Predicate, len(predicates))
+	found := false
+	for i, p := range predicates {
+		fuzzy[i] = p
+		if fp, ok := p.(FuzzyPredicate); ok {
+			fuzzy[i], found = fp.Fuzzy(), true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return rc.search(fuzzy)
 }
 
-func (rc *RecipeCatalog) Search(predicates []Predicate) map[*Recipe][]MatchResult {
-	results := make(map[*Recipe][]MatchResult)
+// search is Search, without falling back to typos.
+func (rc *RecipeCatalog) search(predicates []Predicate) []SearchResult {
+	var results []SearchResult
 
 	for _, r := range rc.Recipes {
+		var matches []MatchResult
+		matched := false
 		for _, p := range predicates {
-			if match, ok := p.Match(r); ok {
-				if _, ok := results[r]; !ok {
-					results[r] = make([]MatchResult, 0)
-				}
-				results[r] = append(results[r], MatchResult{
-					Predicate: p,
-					Match:     match,
-				})
+			if m, ok := matchAll(p, r); ok {
+				matches = append(matches, m...)
+				matched = true
+			}
+		}
+		if matched {
+			result := SearchResult{Recipe: r, Matches: matches}
+			for _, m := range matches {
+				result.Score += m.Score
 			}
+			results = append(results, result)
 		}
 	}
 
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
 	return results
 }
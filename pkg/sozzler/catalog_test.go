@@ -0,0 +1,125 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOverlay(t *testing.T) {
+	shared, personal := t.TempDir(), t.TempDir()
+
+	writeFile(t, filepath.Join(shared, "Aviation.yaml"), "name: Aviation\nrating: 3\n")
+	writeFile(t, filepath.Join(shared, "Negroni.yaml"), "name: Negroni\nrating: 4\n")
+	writeFile(t, filepath.Join(personal, "aviation.yaml"), "name: aviation\nrating: 5\n")
+
+	var catalog sozzler.RecipeCatalog
+	require.NoError(t, catalog.Load(shared, personal))
+
+	assert.Len(t, catalog.Recipes, 2)
+
+	aviation, ok := catalog.Find("Aviation")
+	require.True(t, ok)
+	assert.Equal(t, 5, aviation.Rating)
+
+	negroni, ok := catalog.Find("negroni")
+	require.True(t, ok)
+	assert.Equal(t, 4, negroni.Rating)
+}
+
+func TestLoadMissingDir(t *testing.T) {
+	var catalog sozzler.RecipeCatalog
+	assert.Error(t, catalog.Load(filepath.Join(t.TempDir(), "nope")))
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"recipes/Negroni.yaml":          {Data: []byte("name: Negroni\n")},
+		"recipes/tiki/Mai Tai.yaml":     {Data: []byte("name: Mai Tai\n")},
+		"recipes/classics/Sazerac.yaml": {Data: []byte("name: Sazerac\n")},
+		"recipes/.git/config":           {Data: []byte("[core]\n")},
+		"other/Daiquiri.yaml":           {Data: []byte("name: Daiquiri\n")},
+	}
+
+	var catalog sozzler.RecipeCatalog
+	require.NoError(t, catalog.LoadFS(fsys, "recipes"))
+
+	var names []string
+	for _, r := range catalog.Recipes {
+		names = append(names, r.Name)
+	}
+	assert.ElementsMatch(t, []string{"Negroni", "Mai Tai", "Sazerac"}, names)
+}
+
+func TestLoadFSDecodeError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tiki/Zombie.yaml": {Data: []byte("name: [Zombie\n")},
+	}
+
+	var catalog sozzler.RecipeCatalog
+	err := catalog.LoadFS(fsys, ".")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join("tiki", "Zombie.yaml"))
+}
+
+func TestLoadLenient(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Aviation.yaml": {Data: []byte("name: Aviation\nrating: 4\n")},
+		"Broken.yaml":   {Data: []byte("name: [Broken\n")},
+		"Zombie.yaml": {Data: []byte(`name: Zombie
+components:
+  - ingredient: rum
+    quantity: '1/0'
+    unit: oz
+rating: lots
+`)},
+	}
+
+	catalog := sozzler.RecipeCatalog{Lenient: true}
+	err := catalog.LoadFS(fsys, ".")
+
+	var loadErrs sozzler.LoadErrors
+	require.ErrorAs(t, err, &loadErrs)
+	require.Len(t, loadErrs, 3)
+
+	assert.Equal(t, "Broken.yaml", loadErrs[0].Path)
+	assert.Equal(t, 1, loadErrs[0].Line)
+
+	assert.Equal(t, "Zombie.yaml", loadErrs[1].Path)
+	assert.Equal(t, 4, loadErrs[1].Line)
+	assert.Equal(t, 15, loadErrs[1].Column)
+	assert.Equal(t, "components[0].quantity", loadErrs[1].Field)
+
+	assert.Equal(t, 6, loadErrs[2].Line)
+	assert.Equal(t, 9, loadErrs[2].Column)
+	assert.Equal(t, "rating", loadErrs[2].Field)
+
+	require.Len(t, catalog.Recipes, 1)
+	assert.Equal(t, "Aviation", catalog.Recipes[0].Name)
+}
+
+func TestLoadStrict(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Aviation.yaml": {Data: []byte("name: Aviation\n")},
+		"Broken.yaml":   {Data: []byte("name: [Broken\n")},
+		"Zombie.yaml":   {Data: []byte("name: Zombie\n")},
+	}
+
+	var catalog sozzler.RecipeCatalog
+	err := catalog.LoadFS(fsys, ".")
+
+	var loadErrs sozzler.LoadErrors
+	require.ErrorAs(t, err, &loadErrs)
+	assert.Len(t, loadErrs, 1)
+	assert.Len(t, catalog.Recipes, 1)
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+}
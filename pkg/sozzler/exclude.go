@@ -0,0 +1,183 @@
+package sozzler
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Exclusions are the recipes someone doesn't want to see: those with
+// ingredients they dislike or are allergic to.
+//
+// An exclusions file is YAML, like
+//
+//	ingredients: [egg white, orgeat]
+//	allergens: [nuts]
+type Exclusions struct {
+	// Ingredients excludes recipes with an ingredient matching one of these,
+	// as a NewIngredientWordsPredicate matches.
+	Ingredients []string `yaml:"ingredients,omitempty"`
+	// Allergens excludes recipes with an ingredient tagged with one of these
+	// in the ingredient database.
+	Allergens []string `yaml:"allergens,omitempty"`
+}
+
+// LoadExclusions reads the exclusions file at path.
+func LoadExclusions(path string) (*Exclusions, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var ex Exclusions
+	if _, errs := decodeStrictYAML(path, file, &ex); errs != nil {
+		return nil, errs
+	}
+	return &ex, nil
+}
+
+// Add adds other's exclusions to ex.
+func (ex *Exclusions) Add(other *Exclusions) {
+	ex.Ingredients = append(ex.Ingredients, other.Ingredients...)
+	ex.Allergens = append(ex.Allergens, other.Allergens...)
+}
+
+// Predicate returns a Predicate matching the recipes ex doesn't exclude,
+// made of NotPredicates, or nil if ex excludes nothing. It fails if an
+// allergen isn't tagged on any ingredient in db, as it would exclude nothing.
+func (ex *Exclusions) Predicate(db *IngredientDB) (Predicate, error) {
+	var predicates []Predicate
+	for _, i := range ex.Ingredients {
+		if strings.TrimSpace(i) != "" {
+			predicates = append(predicates, NewNotPredicate(NewIngredientWordsPredicate(i, db)))
+		}
+	}
+	for _, a := range ex.Allergens {
+		if strings.TrimSpace(a) == "" {
+			continue
+		}
+		if !containsFold(db.Allergens(), strings.TrimSpace(a)) {
+			return nil, fmt.Errorf("unknown allergen %q: use one of %s", a, strings.Join(db.Allergens(), ", "))
+		}
+		predicates = append(predicates, NewNotPredicate(NewAllergenPredicate(a, db)))
+	}
+
+	if len(predicates) == 0 {
+		return nil, nil
+	}
+	return NewAndPredicate(predicates...), nil
+}
+
+// Filter returns a copy of rc with only the recipes matching p.
+func (rc *RecipeCatalog) Filter(p Predicate) *RecipeCatalog {
+	filtered := *rc
+	filtered.Recipes = nil
+	for _, r := range rc.Recipes {
+		if _, ok := p.Match(r); ok {
+			filtered.Recipes = append(filtered.Recipes, r)
+		}
+	}
+	return &filtered
+}
+
+// IngredientWordsPredicate
+
+type IngredientWordsPredicate struct {
+	ingredient  string
+	stems       []string
+	ingredients *IngredientDB
+}
+
+// Match matches the first component with the ingredient's words in it, in
+// order and as whole words, or that names a kind of the ingredient in the
+// ingredient database. So egg matches Egg White, and whiskey matches Bourbon,
+// but gin doesn't match Ginger Beer. Unlike an IngredientPredicate, there's
+// no matching part of a word, or typos.
+func (ip *IngredientWordsPredicate) Match(candidate *Recipe) (string, bool) {
+	want, known := ip.ingredients.exact(ip.ingredient)
+	for _, c := range candidate.Components {
+		var stems []string
+		for _, t := range tokenize(c.Ingredient) {
+			stems = append(stems, t.stem)
+		}
+		if containsRun(stems, ip.stems) {
+			return c.Ingredient, true
+		}
+		if !known {
+			continue
+		}
+		for _, in := range ip.ingredients.mentioned(c.Ingredient) {
+			if ip.ingredients.IsA(in, want.Name) {
+				return c.Ingredient, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (ip *IngredientWordsPredicate) Name() string {
+	return "Ingredient"
+}
+
+// NewIngredientWordsPredicate matches recipes with ingredient, by whole words
+// or by kind in ingredients.
+func NewIngredientWordsPredicate(ingredient string, ingredients *IngredientDB) Predicate {
+	ip := &IngredientWordsPredicate{
+		ingredient:  strings.TrimSpace(ingredient),
+		ingredients: ingredients,
+	}
+	for _, t := range tokenize(ingredient) {
+		ip.stems = append(ip.stems, t.stem)
+	}
+	return ip
+}
+
+// containsRun reports whether run is in list, in order and side by side.
+func containsRun(list, run []string) bool {
+	if len(run) == 0 {
+		return false
+	}
+	for i := 0; i+len(run) <= len(list); i++ {
+		if slices.Equal(list[i:i+len(run)], run) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllergenPredicate
+
+type AllergenPredicate struct {
+	allergen    string
+	ingredients *IngredientDB
+}
+
+// Match matches the first component naming an ingredient tagged with the
+// allergen. Every ingredient a component's words name is checked, not just
+// the one it resolves to, so "Orgeat Syrup" has nuts, though it resolves to
+// syrup.
+func (ap *AllergenPredicate) Match(candidate *Recipe) (string, bool) {
+	for _, c := range candidate.Components {
+		for _, in := range ap.ingredients.mentioned(c.Ingredient) {
+			if ap.ingredients.HasAllergen(in, ap.allergen) {
+				return c.Ingredient, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (ap *AllergenPredicate) Name() string {
+	return "Allergen"
+}
+
+// NewAllergenPredicate matches recipes with an ingredient tagged with
+// allergen in ingredients.
+func NewAllergenPredicate(allergen string, ingredients *IngredientDB) Predicate {
+	return &AllergenPredicate{
+		allergen:    strings.TrimSpace(allergen),
+		ingredients: ingredients,
+	}
+}
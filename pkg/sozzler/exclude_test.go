@@ -0,0 +1,284 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusions(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		exclusions sozzler.Exclusions
+		recipe     sozzler.Recipe
+		want       bool
+	}{
+		{
+			desc: "nothing",
+			recipe: sozzler.Recipe{Name: "Whiskey Sour", Components: []sozzler.Component{
+				*component("Bourbon", "2", "oz"),
+				*component("Egg White", "1", ""),
+			}},
+			want: true,
+		},
+		{
+			desc:       "ingredient",
+			exclusions: sozzler.Exclusions{Ingredients: []string{"egg", "Orgeat"}},
+			recipe: sozzler.Recipe{Name: "Whiskey Sour", Components: []sozzler.Component{
+				*component("Bourbon", "2", "oz"),
+				*component("Lemon Juice", "3/4", "oz"),
+				*component("Egg White", "1", ""),
+			}},
+		},
+		{
+			desc:       "another ingredient",
+			exclusions: sozzler.Exclusions{Ingredients: []string{"egg", "Orgeat"}},
+			recipe: sozzler.Recipe{Name: "Mai Tai", Components: []sozzler.Component{
+				*component("Jamaican Rum", "2", "oz"),
+				*component("Orgeat", "1/2", "oz"),
+			}},
+		},
+		{
+			desc:       "ingredient, not part of a word",
+			exclusions: sozzler.Exclusions{Ingredients: []string{"egg"}},
+			recipe: sozzler.Recipe{Name: "Eggnog Cobbler", Components: []sozzler.Component{
+				*component("Eggnog", "4", "oz"),
+			}},
+			want: true,
+		},
+		{
+			desc:       "nuts, by category",
+			exclusions: sozzler.Exclusions{Allergens: []string{"Nuts"}},
+			recipe: sozzler.Recipe{Name: "Amaretto Sour", Components: []sozzler.Component{
+				*component("Amaretto", "2", "oz"),
+				*component("Lemon Juice", "1", "oz"),
+			}},
+		},
+		{
+			desc:       "nuts, by category of an added ingredient",
+			exclusions: sozzler.Exclusions{Allergens: []string{"nuts"}},
+			recipe: sozzler.Recipe{Name: "Pink Squirrel", Components: []sozzler.Component{
+				*component("Crème de Noyaux", "3/4", "oz"),
+				*component("Heavy Cream", "1 1/2", "oz"),
+			}},
+		},
+		{
+			desc:       "dairy",
+			exclusions: sozzler.Exclusions{Allergens: []string{"dairy"}},
+			recipe: sozzler.Recipe{Name: "White Russian", Components: []sozzler.Component{
+				*component("Vodka", "2", "oz"),
+				*component("Coffee Liqueur", "1", "oz"),
+				*component("Cream", "1", "oz"),
+			}},
+		},
+		{
+			desc:       "dairy, not coconut",
+			exclusions: sozzler.Exclusions{Allergens: []string{"dairy"}},
+			recipe: sozzler.Recipe{Name: "Painkiller", Components: []sozzler.Component{
+				*component("Dark Rum", "2", "oz"),
+				*component("Pineapple Juice", "4", "oz"),
+				*component("Coconut Cream", "1", "oz"),
+			}},
+			want: true,
+		},
+		{
+			desc:       "egg, only in the database",
+			exclusions: sozzler.Exclusions{Allergens: []string{"egg"}},
+			recipe: sozzler.Recipe{Name: "Brandy Flip", Components: []sozzler.Component{
+				*component("Brandy", "2", "oz"),
+				*component("Whole Egg", "1", ""),
+			}},
+		},
+		{
+			desc:       "egg, not eggnog",
+			exclusions: sozzler.Exclusions{Allergens: []string{"egg"}},
+			recipe: sozzler.Recipe{Name: "Eggnog Cobbler", Components: []sozzler.Component{
+				*component("Eggnog", "4", "oz"),
+			}},
+			want: true,
+		},
+		{
+			desc:       "both",
+			exclusions: sozzler.Exclusions{Ingredients: []string{"campari", " "}, Allergens: []string{"nuts", "dairy"}},
+			recipe: sozzler.Recipe{Name: "Negroni", Components: []sozzler.Component{
+				*component("Gin", "1", "oz"),
+				*component("Campari", "1", "oz"),
+				*component("Sweet Vermouth", "1", "oz"),
+			}},
+		},
+		{
+			desc:       "both, neither",
+			exclusions: sozzler.Exclusions{Ingredients: []string{"campari", " "}, Allergens: []string{"nuts", "dairy"}},
+			recipe: sozzler.Recipe{Name: "Brandy Flip", Components: []sozzler.Component{
+				*component("Brandy", "2", "oz"),
+				*component("Whole Egg", "1", ""),
+			}},
+			want: true,
+		},
+	}
+
+	db := sozzler.DefaultIngredients()
+	db.Add(&sozzler.Ingredient{Name: "crème de noyaux", Category: "amaretto"})
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			catalog := &sozzler.RecipeCatalog{Ingredients: db, Recipes: []*sozzler.Recipe{&tc.recipe}}
+			filtered := catalog
+			p, err := tc.exclusions.Predicate(catalog.Ingredients)
+			require.NoError(t, err)
+			if p != nil {
+				filtered = catalog.Filter(p)
+			}
+
+			assert.Equal(t, tc.want, len(filtered.Recipes) == 1)
+			assert.Same(t, catalog.Ingredients, filtered.Ingredients)
+			assert.Len(t, catalog.Recipes, 1)
+		})
+	}
+}
+
+func TestIngredientWordsPredicate(t *testing.T) {
+	testCases := []struct {
+		exclude    string
+		ingredient string
+		want       bool
+	}{
+		{"egg", "Egg White", true},
+		{"egg", "Egg Whites", true},
+		{"egg white", "Egg White", true},
+		{"egg white", "Egg Yolk", false},
+		{"egg", "Eggnog", false},
+		{"gin", "Ginger Beer", false},
+		{"gin", "London Dry Gin", true},
+		{"gin", "Tanqueray", false},
+		{"cream", "Crema de Mezcal", false},
+		{"cream", "Heavy Cream", true},
+		{"whiskey", "Bourbon", true},
+		{"Whiskey", "Rye Whiskey", true},
+		{"orgeat", "Orgeat Syrup", true},
+		{"syrup", "Orgeat", true},
+		{"orgeat", "Orgean", false},
+		{"lime juice", "Fresh Lime Juice", true},
+		{"lime juice", "Lime", false},
+	}
+
+	db := sozzler.DefaultIngredients()
+	for _, tc := range testCases {
+		t.Run(tc.exclude+" "+tc.ingredient, func(t *testing.T) {
+			recipe := &sozzler.Recipe{Name: "Test", Components: []sozzler.Component{*component(tc.ingredient, "1", "oz")}}
+			match, ok := sozzler.NewIngredientWordsPredicate(tc.exclude, db).Match(recipe)
+			assert.Equal(t, tc.want, ok)
+			if tc.want {
+				assert.Equal(t, tc.ingredient, match)
+			}
+		})
+	}
+}
+
+func TestExclusionsUnknownAllergen(t *testing.T) {
+	ex := sozzler.Exclusions{Allergens: []string{"shellfish"}}
+	_, err := ex.Predicate(sozzler.DefaultIngredients())
+	assert.EqualError(t, err, `unknown allergen "shellfish": use one of dairy, egg, nuts`)
+}
+
+func TestAllergenPredicate(t *testing.T) {
+	testCases := []struct {
+		recipe    sozzler.Recipe
+		wantMatch string
+		wantOK    bool
+	}{
+		{
+			recipe: sozzler.Recipe{Name: "Mai Tai", Components: []sozzler.Component{
+				*component("Jamaican Rum", "2", "oz"),
+				*component("Lime Juice", "3/4", "oz"),
+				*component("Orgeat", "1/2", "oz"),
+				*component("Orange Curaçao", "1/2", "oz"),
+			}},
+			wantMatch: "Orgeat",
+			wantOK:    true,
+		},
+		{
+			recipe: sozzler.Recipe{Name: "Pink Squirrel", Components: []sozzler.Component{
+				*component("Crème de Noyaux", "3/4", "oz"),
+				*component("White Crème de Cacao", "3/4", "oz"),
+				*component("Heavy Cream", "1 1/2", "oz"),
+			}},
+			wantMatch: "Crème de Noyaux",
+			wantOK:    true,
+		},
+		{
+			recipe: sozzler.Recipe{Name: "Whiskey Sour", Components: []sozzler.Component{
+				*component("Bourbon", "2", "oz"),
+				*component("Simple Syrup", "3/4", "oz"),
+				*component("Egg White", "1", ""),
+			}},
+		},
+	}
+
+	db := sozzler.DefaultIngredients()
+	db.Add(&sozzler.Ingredient{Name: "crème de noyaux", Category: "amaretto"})
+	for _, tc := range testCases {
+		t.Run(tc.recipe.Name, func(t *testing.T) {
+			match, ok := sozzler.NewAllergenPredicate("nuts", db).Match(&tc.recipe)
+			assert.Equal(t, tc.wantMatch, match)
+			assert.Equal(t, tc.wantOK, ok)
+		})
+	}
+}
+
+func TestAllergenPredicateNames(t *testing.T) {
+	testCases := []struct {
+		ingredient string
+		allergen   string
+		want       bool
+	}{
+		{"Orgeat", "nuts", true},
+		{"Orgeat Syrup", "nuts", true},
+		{"Amaretto Liqueur", "nuts", true},
+		{"Disaronno Amaretto", "nuts", true},
+		{"Almond Milk", "nuts", true},
+		{"Almond Milk", "dairy", false},
+		{"Whole Milk", "dairy", true},
+		{"Frangelico", "nuts", true},
+		{"Coconut Cream", "dairy", false},
+		{"Heavy Cream", "dairy", true},
+		{"Egg Whites", "egg", true},
+		{"Simple Syrup", "nuts", false},
+		{"Nutmeg", "nuts", false},
+	}
+
+	db := sozzler.DefaultIngredients()
+	for _, tc := range testCases {
+		t.Run(tc.ingredient+" "+tc.allergen, func(t *testing.T) {
+			recipe := &sozzler.Recipe{Name: "Test", Components: []sozzler.Component{*component(tc.ingredient, "1", "oz")}}
+			match, ok := sozzler.NewAllergenPredicate(tc.allergen, db).Match(recipe)
+			assert.Equal(t, tc.want, ok)
+			if tc.want {
+				assert.Equal(t, tc.ingredient, match)
+			}
+		})
+	}
+}
+
+func TestLoadExclusions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exclusions.yaml")
+	writeFile(t, path, "ingredients: [egg white, orgeat]\nallergens:\n  - nuts\n")
+
+	ex, err := sozzler.LoadExclusions(path)
+	require.NoError(t, err)
+	assert.Equal(t, &sozzler.Exclusions{Ingredients: []string{"egg white", "orgeat"}, Allergens: []string{"nuts"}}, ex)
+
+	writeFile(t, path, "allergens: nuts\n")
+	_, err = sozzler.LoadExclusions(path)
+	var loadErrs sozzler.LoadErrors
+	require.ErrorAs(t, err, &loadErrs)
+	assert.Equal(t, 1, loadErrs[0].Line)
+
+	writeFile(t, path, "ingredients: [orgeat]\nallergen: [nuts]\n")
+	_, err = sozzler.LoadExclusions(path)
+	require.ErrorAs(t, err, &loadErrs)
+	require.Len(t, loadErrs, 1)
+	assert.Equal(t, path+":2:1: allergen: field allergen not found in type sozzler.Exclusions", loadErrs[0].Error())
+}
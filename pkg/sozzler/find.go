@@ -0,0 +1,122 @@
+package sozzler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSuggestions is how many recipes a NotFoundError suggests.
+const maxSuggestions = 5
+
+// NotFoundError is a recipe Lookup couldn't find, with the recipes that might
+// have been meant, best first.
+type NotFoundError struct {
+	Name        string
+	Suggestions []*Recipe
+}
+
+// Error says what wasn't found, and suggests what might have been meant:
+//
+//	couldn't find recipe "negr": did you mean "Negroni" or "Negroni Sbagliato"?
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("couldn't find recipe %q", e.Name)
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	var names []string
+	for _, r := range e.Suggestions {
+		names = append(names, fmt.Sprintf("%q", r.Name))
+	}
+	if len(names) > 1 {
+		names[len(names)-2] += " or " + names[len(names)-1]
+		names = names[:len(names)-1]
+	}
+	return fmt.Sprintf("%s: did you mean %s?", msg, strings.Join(names, ", "))
+}
+
+// Find returns the recipe called name, ignoring case, accents, spacing, and
+// the kind of apostrophe, so "vieux carre" finds Vieux Carré and "Bee's Knees"
+// finds Bee’s Knees.
+func (rc *RecipeCatalog) Find(name string) (*Recipe, bool) {
+	name = foldName(name)
+	for _, r := range rc.Recipes {
+		if foldName(r.Name) == name {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Lookup is Find, falling back to the recipe name names uniquely: the only
+// name within a typo or two of it, or else the only name it's the start of,
+// part of, or a typo or two from part of. When there's no such recipe, it
+// fails with a *NotFoundError suggesting the closest names.
+func (rc *RecipeCatalog) Lookup(name string) (*Recipe, error) {
+	if r, ok := rc.Find(name); ok {
+		return r, nil
+	}
+
+	type candidate struct {
+		recipe   *Recipe
+		score    float64
+		distance int
+	}
+	term := foldName(name)
+	var candidates []candidate
+	var near []*Recipe
+	for _, r := range rc.Recipes {
+		folded := foldName(r.Name)
+		score := scoreText(term, folded, true)
+		if score == 0 {
+			continue
+		}
+		distance := editDistance(term, folded)
+		if distance <= typos(term) {
+			near = append(near, r)
+		}
+		candidates = append(candidates, candidate{recipe: r, score: score, distance: distance})
+	}
+
+	switch {
+	case len(near) == 1:
+		return near[0], nil
+	case len(candidates) == 1:
+		return candidates[0].recipe, nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].distance < candidates[j].distance
+	})
+	err := &NotFoundError{Name: name}
+	for _, c := range candidates {
+		if len(err.Suggestions) == maxSuggestions {
+			break
+		}
+		err.Suggestions = append(err.Suggestions, c.recipe)
+	}
+	return nil, err
+}
+
+// foldName folds a recipe name for comparing: lower case, without accents,
+// with straight apostrophes, and single spaces.
+func foldName(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// an accent, split off its letter by NFD
+		case r == '’' || r == '‘' || r == '`' || r == '´' || r == 'ʼ':
+			b.WriteRune('\'')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
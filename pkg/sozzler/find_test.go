@@ -0,0 +1,135 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	testCases := []struct {
+		name    string
+		recipes []*sozzler.Recipe
+		want    string
+	}{
+		{"Vieux Carré", []*sozzler.Recipe{{Name: "Negroni"}, {Name: "Vieux Carré"}}, "Vieux Carré"},
+		{"vieux carre", []*sozzler.Recipe{{Name: "Negroni"}, {Name: "Vieux Carré"}}, "Vieux Carré"},
+		{"VIEUX  CARRE", []*sozzler.Recipe{{Name: "Vieux Carré"}}, "Vieux Carré"},
+		{"Bee's Knees", []*sozzler.Recipe{{Name: "Bee’s Knees"}}, "Bee’s Knees"},
+		{"bee’s knees", []*sozzler.Recipe{{Name: "Bee’s Knees"}}, "Bee’s Knees"},
+		{"Bee`s Knees", []*sozzler.Recipe{{Name: "Bee’s Knees"}}, "Bee’s Knees"},
+		{"negroni", []*sozzler.Recipe{{Name: "Negroni Sbagliato"}, {Name: "Negroni"}}, "Negroni"},
+		{"Negoni", []*sozzler.Recipe{{Name: "Negroni"}}, ""},
+		{"Bees Knees", []*sozzler.Recipe{{Name: "Bee’s Knees"}}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			catalog := &sozzler.RecipeCatalog{Recipes: tc.recipes}
+			recipe, ok := catalog.Find(tc.name)
+			if tc.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tc.want, recipe.Name)
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	testCases := []struct {
+		name        string
+		recipes     []*sozzler.Recipe
+		want        string
+		suggestions []string
+	}{
+		{
+			name:    "vieux carre",
+			recipes: []*sozzler.Recipe{{Name: "Negroni"}, {Name: "Vieux Carré"}},
+			want:    "Vieux Carré",
+		},
+		{
+			name:    "Negoni",
+			recipes: []*sozzler.Recipe{{Name: "Negroni"}, {Name: "Negroni Sbagliato"}, {Name: "Gin Fizz"}},
+			want:    "Negroni",
+		},
+		{
+			name:    "Bees Knees",
+			recipes: []*sozzler.Recipe{{Name: "Bee’s Knees"}, {Name: "Gin Fizz"}},
+			want:    "Bee’s Knees",
+		},
+		{
+			name:    "daiq",
+			recipes: []*sozzler.Recipe{{Name: "Negroni"}, {Name: "Daiquiri"}},
+			want:    "Daiquiri",
+		},
+		{
+			name:    "vieux",
+			recipes: []*sozzler.Recipe{{Name: "Vieux Carré"}, {Name: "Negroni"}},
+			want:    "Vieux Carré",
+		},
+		{
+			name:    "negroni sbagliatto",
+			recipes: []*sozzler.Recipe{{Name: "Negroni"}, {Name: "Negroni Sbagliato"}},
+			want:    "Negroni Sbagliato",
+		},
+		{
+			name:        "negr",
+			recipes:     []*sozzler.Recipe{{Name: "Negroni"}, {Name: "Negroni Sbagliato"}, {Name: "Daiquiri"}},
+			suggestions: []string{"Negroni", "Negroni Sbagliato"},
+		},
+		{
+			name:        "gin",
+			recipes:     []*sozzler.Recipe{{Name: "Negroni"}, {Name: "Gin Fizz"}, {Name: "Pink Gin"}},
+			suggestions: []string{"Gin Fizz", "Pink Gin"},
+		},
+		{
+			name:    "Martini",
+			recipes: []*sozzler.Recipe{{Name: "Negroni"}, {Name: "Daiquiri"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			catalog := &sozzler.RecipeCatalog{Recipes: tc.recipes}
+			recipe, err := catalog.Lookup(tc.name)
+			if tc.want != "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, recipe.Name)
+				return
+			}
+
+			var notFound *sozzler.NotFoundError
+			require.ErrorAs(t, err, &notFound)
+			assert.Equal(t, tc.name, notFound.Name)
+			var names []string
+			for _, r := range notFound.Suggestions {
+				names = append(names, r.Name)
+			}
+			assert.Equal(t, tc.suggestions, names)
+		})
+	}
+}
+
+func TestNotFoundError(t *testing.T) {
+	negroni, sbagliato, fizz := &sozzler.Recipe{Name: "Negroni"}, &sozzler.Recipe{Name: "Negroni Sbagliato"}, &sozzler.Recipe{Name: "Gin Fizz"}
+
+	testCases := []struct {
+		err  *sozzler.NotFoundError
+		want string
+	}{
+		{&sozzler.NotFoundError{Name: "Martini"}, `couldn't find recipe "Martini"`},
+		{&sozzler.NotFoundError{Name: "negr", Suggestions: []*sozzler.Recipe{negroni}}, `couldn't find recipe "negr": did you mean "Negroni"?`},
+		{&sozzler.NotFoundError{Name: "negr", Suggestions: []*sozzler.Recipe{negroni, sbagliato}}, `couldn't find recipe "negr": did you mean "Negroni" or "Negroni Sbagliato"?`},
+		{&sozzler.NotFoundError{Name: "n", Suggestions: []*sozzler.Recipe{negroni, sbagliato, fizz}}, `couldn't find recipe "n": did you mean "Negroni", "Negroni Sbagliato" or "Gin Fizz"?`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.err.Error())
+		})
+	}
+}
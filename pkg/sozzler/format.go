@@ -0,0 +1,43 @@
+package sozzler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EncodeRecipe writes r to w as a canonical recipe file: keys in a fixed order,
+// quantities as reduced fractions, unmeasured quantities and empty units left
+// out, and multi-line notes as a block scalar.
+func EncodeRecipe(w io.Writer, r *Recipe) error {
+	canonical := *r
+	canonical.Notes = strings.TrimSpace(r.Notes)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&canonical); err != nil {
+		return fmt.Errorf("couldn't encode recipe %q: %w", r.Name, err)
+	}
+	return enc.Close()
+}
+
+// Format returns the recipe file src in canonical form. It fails on fields that
+// aren't part of a recipe rather than silently dropping them.
+func Format(src []byte) ([]byte, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(src))
+	dec.KnownFields(true)
+
+	var recipe Recipe
+	if err := dec.Decode(&recipe); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeRecipe(&buf, &recipe); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
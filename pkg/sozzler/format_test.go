@@ -0,0 +1,58 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	given := `---
+text: |
+  Shake with ice.
+
+  Garnish with lime shell.
+components:
+  - quantity: '2/1'
+    unit: 'oz'
+    ingredient: 'Jamaican Rum'
+  - quantity: '6/8'
+    ingredient: 'Lime Juice'
+    unit: 'oz'
+  - quantity: '0/1'
+    ingredient: 'Lime Shell'
+    unit: ''
+name: 'Mai Tai'
+rating: 4
+`
+	want := `name: Mai Tai
+rating: 4
+components:
+  - ingredient: Jamaican Rum
+    quantity: "2"
+    unit: oz
+  - ingredient: Lime Juice
+    quantity: 3/4
+    unit: oz
+  - ingredient: Lime Shell
+text: |-
+  Shake with ice.
+
+  Garnish with lime shell.
+`
+
+	got, err := sozzler.Format([]byte(given))
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got))
+
+	again, err := sozzler.Format(got)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(again))
+}
+
+func TestFormatUnknownField(t *testing.T) {
+	_, err := sozzler.Format([]byte("name: Mai Tai\nglass: rocks\n"))
+	assert.Error(t, err)
+}
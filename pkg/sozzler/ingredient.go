@@ -0,0 +1,338 @@
+package sozzler
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IngredientsFile is the name of the ingredient database in a recipe
+// directory. It's loaded along with the recipes, and isn't a recipe itself.
+const IngredientsFile = "ingredients.yaml"
+
+// Perishability is how long an ingredient keeps once it's opened or made.
+type Perishability string
+
+const (
+	// Shelf ingredients keep for months, like spirits and bitters.
+	Shelf Perishability = "shelf"
+	// Refrigerated ingredients keep for weeks in the fridge, like vermouth
+	// and syrups.
+	Refrigerated Perishability = "refrigerated"
+	// Fresh ingredients are best the day they're made, like citrus juice.
+	Fresh Perishability = "fresh"
+)
+
+// Ingredient is an entry in an ingredient database.
+type Ingredient struct {
+	Name string `yaml:"name"`
+	// Category is the name of the broader ingredient this is a kind of, like
+	// gin for London dry gin.
+	Category string `yaml:"category,omitempty"`
+	// ABV is alcohol by volume, in percent, or nil if it isn't known.
+	ABV *float64 `yaml:"abv,omitempty"`
+	// Sugar and Acid are grams per 100 ml.
+	Sugar         float64       `yaml:"sugar,omitempty"`
+	Acid          float64       `yaml:"acid,omitempty"`
+	Perishability Perishability `yaml:"perishability,omitempty"`
+	// Bottle is the size, in ml, of the bottle the ingredient is sold in, if
+	// it's sold by the bottle.
+	Bottle float64 `yaml:"bottle,omitempty"`
+	// Source is what the ingredient is made from, like lime for lime juice,
+	// and Yield is how many ml one Source makes.
+	Source  string   `yaml:"source,omitempty"`
+	Yield   float64  `yaml:"yield,omitempty"`
+	Aliases []string `yaml:"aliases,omitempty"`
+	// Allergens are tags like nuts or dairy, for people who can't have the
+	// ingredient. An ingredient has its category's allergens too.
+	Allergens []string `yaml:"allergens,omitempty"`
+}
+
+// IngredientDB resolves ingredient names, as they're written in recipes, to
+// Ingredients.
+type IngredientDB struct {
+	// entries are the ingredients as they were added.
+	entries []*Ingredient
+	// byName maps lower case names and aliases to ingredients, with ABV,
+	// Perishability, Bottle, and Allergens filled in from their categories.
+	byName map[string]*Ingredient
+	// loadErrs are the problems found loading IngredientsFiles into db.
+	loadErrs LoadErrors
+}
+
+//go:embed ingredients.yaml
+var ingredientsYAML []byte
+
+// defaultIngredients are the ingredients in the embedded ingredients.yaml.
+var defaultIngredients = func() []*Ingredient {
+	var ingredients []*Ingredient
+	if err := yaml.Unmarshal(ingredientsYAML, &ingredients); err != nil {
+		panic(err)
+	}
+	return ingredients
+}()
+
+// DefaultIngredients returns a new database of the ingredients sozzler knows
+// about. Adding to it doesn't change the defaults.
+func DefaultIngredients() *IngredientDB {
+	db := &IngredientDB{}
+	db.Add(defaultIngredients...)
+	return db
+}
+
+// LookupIngredient resolves name in the default ingredient database.
+func LookupIngredient(name string) (*Ingredient, bool) {
+	return defaultDB.Resolve(name)
+}
+
+var defaultDB = DefaultIngredients()
+
+// Add adds ingredients to db. An ingredient replaces one already in db with
+// the same name, ignoring case. Nil ingredients are skipped.
+func (db *IngredientDB) Add(ingredients ...*Ingredient) {
+	for _, in := range ingredients {
+		if in == nil {
+			continue
+		}
+		replaced := false
+		for i, e := range db.entries {
+			if strings.EqualFold(e.Name, in.Name) {
+				db.entries[i], replaced = in, true
+				break
+			}
+		}
+		if !replaced {
+			db.entries = append(db.entries, in)
+		}
+	}
+	db.index()
+}
+
+// index rebuilds byName from entries.
+func (db *IngredientDB) index() {
+	raw := make(map[string]*Ingredient)
+	for _, e := range db.entries {
+		raw[strings.ToLower(e.Name)] = e
+	}
+
+	resolved := make([]*Ingredient, len(db.entries))
+	for i, e := range db.entries {
+		in := *e
+		// depth guards against categories that loop
+		for parent, depth := raw[strings.ToLower(in.Category)], 0; parent != nil && depth < len(raw); depth++ {
+			if in.ABV == nil {
+				in.ABV = parent.ABV
+			}
+			if in.Perishability == "" {
+				in.Perishability = parent.Perishability
+			}
+			if in.Bottle == 0 {
+				in.Bottle = parent.Bottle
+			}
+			for _, a := range parent.Allergens {
+				if !containsFold(in.Allergens, a) {
+					in.Allergens = append(in.Allergens[:len(in.Allergens):len(in.Allergens)], a)
+				}
+			}
+			parent = raw[strings.ToLower(parent.Category)]
+		}
+		resolved[i] = &in
+	}
+
+	// names win over aliases, so an alias can't hide an ingredient
+	db.byName = make(map[string]*Ingredient)
+	for _, in := range resolved {
+		for _, alias := range in.Aliases {
+			db.byName[strings.ToLower(alias)] = in
+		}
+	}
+	for _, in := range resolved {
+		db.byName[strings.ToLower(in.Name)] = in
+	}
+}
+
+// Resolve finds the ingredient name refers to: by name or alias, ignoring
+// case; then without a plural "s"; then without leading words, so "Fresh
+// Mint" and "Barr Hill Gin" resolve even if they aren't listed.
+func (db *IngredientDB) Resolve(name string) (*Ingredient, bool) {
+	words := strings.Fields(strings.ToLower(name))
+	for i := range words {
+		candidate := strings.Join(words[i:], " ")
+		if in, ok := db.byName[candidate]; ok {
+			return in, true
+		}
+		if in, ok := db.byName[strings.TrimSuffix(candidate, "s")]; ok && strings.HasSuffix(candidate, "s") {
+			return in, true
+		}
+	}
+	return nil, false
+}
+
+// exact finds the ingredient called name, or its plural, without dropping
+// any words.
+func (db *IngredientDB) exact(name string) (*Ingredient, bool) {
+	name = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	if in, ok := db.byName[name]; ok {
+		return in, true
+	}
+	in, ok := db.byName[strings.TrimSuffix(name, "s")]
+	return in, ok && strings.HasSuffix(name, "s")
+}
+
+// mentioned finds every ingredient named by a run of name's words, longest
+// runs first, skipping words that are part of a longer run already found. So
+// "Orgeat Syrup" names orgeat and syrup, but "Coconut Cream" names only cream
+// of coconut, not cream.
+func (db *IngredientDB) mentioned(name string) []*Ingredient {
+	var found []*Ingredient
+	words := strings.Fields(name)
+	covered := make([]bool, len(words))
+	for n := len(words); n > 0; n-- {
+		for i := 0; i+n <= len(words); i++ {
+			if slices.Contains(covered[i:i+n], true) {
+				continue
+			}
+			if in, ok := db.exact(strings.Join(words[i:i+n], " ")); ok {
+				found = append(found, in)
+				for j := i; j < i+n; j++ {
+					covered[j] = true
+				}
+			}
+		}
+	}
+	return found
+}
+
+// Category returns the category of in, if it has one.
+func (db *IngredientDB) Category(in *Ingredient) (*Ingredient, bool) {
+	if in.Category == "" {
+		return nil, false
+	}
+	parent, ok := db.byName[strings.ToLower(in.Category)]
+	return parent, ok
+}
+
+// IsA reports whether in is the ingredient called category, or a kind of it,
+// like London dry gin is a gin and a spirit.
+func (db *IngredientDB) IsA(in *Ingredient, category string) bool {
+	for depth := 0; in != nil && depth <= len(db.entries); depth++ {
+		if strings.EqualFold(in.Name, category) {
+			return true
+		}
+		in, _ = db.Category(in)
+	}
+	return false
+}
+
+// HasAllergen reports whether in is tagged with allergen, ignoring case.
+func (db *IngredientDB) HasAllergen(in *Ingredient, allergen string) bool {
+	return containsFold(in.Allergens, allergen)
+}
+
+// Allergens returns the allergen tags of the ingredients in db, lower case
+// and sorted.
+func (db *IngredientDB) Allergens() []string {
+	var allergens []string
+	for _, in := range db.byName {
+		for _, a := range in.Allergens {
+			if a = strings.ToLower(a); !slices.Contains(allergens, a) {
+				allergens = append(allergens, a)
+			}
+		}
+	}
+	sort.Strings(allergens)
+	return allergens
+}
+
+// containsFold reports whether list has s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadIngredients adds the IngredientsFile at the top of root in fsys, if
+// there is one, to db. Paths in errors are joined onto dir.
+func loadIngredients(db *IngredientDB, fsys fs.FS, root string, dir string) LoadErrors {
+	if info, err := fs.Stat(fsys, root); err == nil && !info.IsDir() {
+		root = path.Dir(root)
+	}
+	name := path.Join(root, IngredientsFile)
+	filename := filepath.Join(dir, filepath.FromSlash(name))
+
+	file, err := fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return LoadErrors{{Path: filename, Err: err}}
+	}
+	defer func() { _ = file.Close() }()
+
+	var decoded []*Ingredient
+	doc, errs := decodeYAML(filename, file, &decoded)
+	if errs != nil {
+		db.loadErrs = append(db.loadErrs, errs...)
+		return errs
+	}
+
+	// empty entries, like "- ~", decode to nil, and nameless ones can't be
+	// looked up by what they're called
+	entries := doc.Content[0].Content
+	var ingredients []*Ingredient
+	var nodes []*yaml.Node
+	for i, in := range decoded {
+		switch {
+		case in == nil:
+			errs = append(errs, ingredientError(filename, entries[i], "", "ingredient is empty"))
+			continue
+		case strings.TrimSpace(in.Name) == "":
+			errs = append(errs, ingredientError(filename, entries[i], "name", "ingredient has no name"))
+			continue
+		}
+		ingredients = append(ingredients, in)
+		nodes = append(nodes, entries[i])
+	}
+	db.Add(ingredients...)
+
+	// categories are checked once everything is added, as they may be defined
+	// later in the file
+	for i, in := range ingredients {
+		if _, ok := db.Category(in); in.Category != "" && !ok {
+			msg := fmt.Sprintf("%q has unknown category %q", in.Name, in.Category)
+			errs = append(errs, ingredientError(filename, nodes[i], "category", msg))
+		}
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Line < errs[j].Line
+	})
+	db.loadErrs = append(db.loadErrs, errs...)
+	return errs
+}
+
+// ingredientError is a LoadError at key in the ingredient node.
+func ingredientError(filename string, node *yaml.Node, key string, msg string) *LoadError {
+	loadErr := &LoadError{Path: filename, Field: key, Err: errors.New(msg)}
+	if n := field(node, key); n != nil {
+		node = n
+	}
+	loadErr.Line, loadErr.Column = node.Line, node.Column
+	return loadErr
+}
+
+// isIngredientsFile reports whether name, found while walking root, is the
+// ingredient database rather than a recipe.
+func isIngredientsFile(name string, root string) bool {
+	return path.Base(name) == IngredientsFile && (name == root || path.Dir(name) == root)
+}
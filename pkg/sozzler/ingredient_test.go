@@ -0,0 +1,158 @@
+package sozzler_test
+
+import (
+	"fmt"
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	testCases := []struct {
+		given string
+		want  string
+	}{
+		{given: "gin", want: "gin"},
+		{given: "Lemon Juice", want: "lemon juice"},
+		{given: "Luxardo Liqueur", want: "maraschino liqueur"},
+		{given: "Barr Hill Gin", want: "barr hill gin"},
+		{given: "Plymouth Gin", want: "gin"},
+		{given: "Fresh Mint", want: "mint"},
+		{given: "Sugar Cubes", want: "sugar cube"},
+		{given: "  orange   juice ", want: "orange juice"},
+		{given: "unobtainium", want: ""},
+		{given: "", want: ""},
+	}
+	db := sozzler.DefaultIngredients()
+	for _, tC := range testCases {
+		t.Run(tC.given, func(t *testing.T) {
+			in, ok := db.Resolve(tC.given)
+			if tC.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tC.want, in.Name)
+		})
+	}
+}
+
+func TestIngredientCategories(t *testing.T) {
+	db := sozzler.DefaultIngredients()
+
+	in, ok := db.Resolve("London Dry Gin")
+	require.True(t, ok)
+	assert.True(t, db.IsA(in, "london dry gin"))
+	assert.True(t, db.IsA(in, "Gin"))
+	assert.True(t, db.IsA(in, "spirit"))
+	assert.False(t, db.IsA(in, "rum"))
+
+	require.NotNil(t, in.ABV, "inherited from gin")
+	assert.Equal(t, 40.0, *in.ABV)
+	assert.Equal(t, sozzler.Shelf, in.Perishability, "inherited from spirit")
+
+	juice, ok := db.Resolve("lime juice")
+	require.True(t, ok)
+	assert.Equal(t, sozzler.Fresh, juice.Perishability)
+	assert.Equal(t, 6.0, juice.Acid)
+}
+
+func TestIngredientsFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ingredients.yaml": {Data: []byte(`
+- name: falernum
+  category: liqueur
+  abv: 11
+  sugar: 30
+- name: gin
+  category: spirit
+  abv: 47
+  aliases: [house gin]
+`)},
+		"Corn 'n' Oil.yaml": {Data: []byte("name: Corn 'n' Oil\ncomponents:\n  - ingredient: falernum\n")},
+	}
+
+	catalog := sozzler.RecipeCatalog{}
+	require.NoError(t, catalog.LoadFS(fsys, "."))
+	assert.Len(t, catalog.Recipes, 1, "ingredients.yaml isn't a recipe")
+
+	falernum, ok := catalog.Ingredients.Resolve("Falernum")
+	require.True(t, ok)
+	assert.True(t, catalog.Ingredients.IsA(falernum, "liqueur"))
+
+	gin, ok := catalog.Ingredients.Resolve("House Gin")
+	require.True(t, ok)
+	assert.Equal(t, 47.0, *gin.ABV, "replaces the default")
+
+	london, ok := catalog.Ingredients.Resolve("london dry gin")
+	require.True(t, ok)
+	assert.Equal(t, 47.0, *london.ABV, "inherited from the replacement")
+
+	_, ok = sozzler.LookupIngredient("falernum")
+	assert.False(t, ok, "defaults are unchanged")
+}
+
+func TestIngredientsFileErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ingredients.yaml": {Data: []byte("- name: falernum\n  category: cordial\n- abv: 40\n  aliases: [foo]\n")},
+		"Daiquiri.yaml":    {Data: []byte("name: Daiquiri\ncomponents:\n  - ingredient: rum\n  - ingredient: Mystery Cordial\n")},
+	}
+
+	catalog := sozzler.RecipeCatalog{Lenient: true}
+	err := catalog.LoadFS(fsys, ".")
+	var loadErrs sozzler.LoadErrors
+	require.ErrorAs(t, err, &loadErrs)
+	require.Len(t, loadErrs, 2)
+	assert.Equal(t, `ingredients.yaml:2:13: category: "falernum" has unknown category "cordial"`, loadErrs[0].Error())
+	assert.Equal(t, 3, loadErrs[1].Line)
+	assert.Len(t, catalog.Recipes, 1)
+	_, ok := catalog.Ingredients.Resolve("foo")
+	assert.False(t, ok, "nameless ingredients aren't added")
+
+	diagnostics, err := sozzler.LintFS(fsys, ".")
+	require.NoError(t, err)
+	var rules []string
+	for _, d := range diagnostics {
+		rules = append(rules, d.Path+" "+d.Rule)
+	}
+	assert.Equal(t, []string{
+		"Daiquiri.yaml unknown-ingredient",
+		"ingredients.yaml ingredients",
+		"ingredients.yaml ingredients",
+	}, rules)
+}
+
+func TestIngredientsFileEmptyEntry(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ingredients.yaml": {Data: []byte("- name: falernum\n  category: liqueur\n- ~\n-\n")},
+		"Daiquiri.yaml":    {Data: []byte("name: Daiquiri\ncomponents:\n  - ingredient: rum\n  - ingredient: falernum\n")},
+	}
+
+	catalog := sozzler.RecipeCatalog{Lenient: true}
+	err := catalog.LoadFS(fsys, ".")
+	var loadErrs sozzler.LoadErrors
+	require.ErrorAs(t, err, &loadErrs)
+	require.Len(t, loadErrs, 2)
+	assert.Equal(t, "ingredients.yaml:3:3: ingredient is empty", loadErrs[0].Error())
+	assert.Equal(t, 4, loadErrs[1].Line)
+	assert.Len(t, catalog.Recipes, 1)
+	_, ok := catalog.Ingredients.Resolve("falernum")
+	assert.True(t, ok)
+
+	strict := sozzler.RecipeCatalog{}
+	require.ErrorAs(t, strict.LoadFS(fsys, "."), &loadErrs)
+
+	diagnostics, err := sozzler.LintFS(fsys, ".")
+	require.NoError(t, err)
+	var rules []string
+	for _, d := range diagnostics {
+		rules = append(rules, fmt.Sprintf("%s:%d %s", d.Path, d.Line, d.Rule))
+	}
+	assert.Equal(t, []string{
+		"ingredients.yaml:3 ingredients",
+		"ingredients.yaml:4 ingredients",
+	}, rules)
+}
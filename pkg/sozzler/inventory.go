@@ -0,0 +1,194 @@
+package sozzler
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Inventory is what's in a home bar: the ingredients on hand, and how much of
+// each, if it's known.
+//
+// An inventory file is a YAML list. Each item is either a line like a recipe
+// component, such as "750 ml London Dry Gin" or "Angostura Bitters", or a
+// mapping with the same fields as a recipe component.
+type Inventory struct {
+	Items []Component
+}
+
+// LoadInventory reads the inventory file at path.
+func LoadInventory(path string) (*Inventory, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var items []inventoryItem
+	if _, errs := decodeYAML(path, file, &items); errs != nil {
+		return nil, errs
+	}
+
+	inv := &Inventory{}
+	for _, item := range items {
+		inv.Items = append(inv.Items, Component(item))
+	}
+	return inv, nil
+}
+
+// inventoryItem decodes an item of an inventory file.
+type inventoryItem Component
+
+func (item *inventoryItem) UnmarshalYAML(value *yaml.Node) error {
+	// errors are yaml.TypeErrors, like Quantity's, so they're reported by line
+	if value.Kind == yaml.ScalarNode {
+		c, err := parseInventoryLine(value.Value)
+		if err != nil {
+			return &yaml.TypeError{Errors: []string{fmt.Sprintf("line %d: %v", value.Line, err)}}
+		}
+		*item = inventoryItem(c)
+		return nil
+	}
+
+	var c Component
+	if err := value.Decode(&c); err != nil {
+		return err
+	}
+	if strings.TrimSpace(c.Ingredient) == "" {
+		return &yaml.TypeError{Errors: []string{fmt.Sprintf("line %d: missing ingredient", value.Line)}}
+	}
+	*item = inventoryItem(c)
+	return nil
+}
+
+// parseInventoryLine parses an inventory item written like "750 ml gin".
+// Unlike ParseComponent, the ingredient is the rest of the line as written, so
+// names like "Peychaud's Bitters" are kept whole.
+func parseInventoryLine(line string) (Component, error) {
+	q, rest, err := quantityPrefix(line)
+	if err != nil {
+		return Component{}, err
+	}
+	c := Component{Quantity: q}
+	words := strings.Fields(rest)
+	if u, n := unitPrefix(words); u != nil {
+		c.Unit = u.Name
+		words = words[n:]
+	}
+	c.Ingredient = strings.Join(words, " ")
+	if c.Ingredient == "" {
+		return Component{}, fmt.Errorf("missing ingredient in %q", line)
+	}
+	return c, nil
+}
+
+// staples are assumed to be in every bar.
+var staples = []string{"water", "ice"}
+
+// Has reports whether the inventory has ingredient, or something that can
+// stand in for it: a kind of it in db, like London dry gin for gin. Only an
+// ingredient db knows by its whole name can be stood in for, so Orange Bitters
+// doesn't stand in for Peach Bitters just because db knows bitters. Items
+// with a quantity of zero have run out. Water and ice are always on hand.
+func (inv *Inventory) Has(db *IngredientDB, ingredient string) bool {
+	items, staple := inv.stock(db, ingredient)
+	return staple || len(items) > 0
+}
+
+// stock returns the items in the inventory that can be used for ingredient,
+// and whether it's a staple, which is always on hand.
+func (inv *Inventory) stock(db *IngredientDB, ingredient string) ([]Component, bool) {
+	if exact, ok := db.exact(ingredient); ok {
+		for _, s := range staples {
+			if db.IsA(exact, s) {
+				return nil, true
+			}
+		}
+	}
+
+	var items []Component
+	want, wok := db.exact(ingredient)
+	for _, item := range inv.Items {
+		if item.Quantity.IsZeroAmount() {
+			continue
+		}
+		if have, hok := db.Resolve(item.Ingredient); wok && hok {
+			if db.IsA(have, want.Name) {
+				items = append(items, item)
+			}
+			continue
+		}
+		if normalizeIngredient(item.Ingredient) == normalizeIngredient(ingredient) {
+			items = append(items, item)
+		}
+	}
+	return items, false
+}
+
+// Optional reports whether a recipe can be made without c: it's a garnish.
+func (db *IngredientDB) Optional(c Component) bool {
+	for _, w := range strings.Fields(strings.ToLower(c.Ingredient)) {
+		if w == "garnish" {
+			return true
+		}
+	}
+	in, ok := db.Resolve(c.Ingredient)
+	return ok && db.IsA(in, "garnish")
+}
+
+// Missing returns the components of r that aren't in the inventory, leaving
+// out optional ones.
+func (inv *Inventory) Missing(db *IngredientDB, r *Recipe) []Component {
+	var missing []Component
+	for _, c := range r.Components {
+		if !db.Optional(c) && !inv.Has(db, c.Ingredient) {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// normalizeIngredient folds case, spacing, and a plural "s", for comparing
+// ingredients that aren't in the database.
+func normalizeIngredient(name string) string {
+	return strings.TrimSuffix(strings.Join(strings.Fields(strings.ToLower(name)), " "), "s")
+}
+
+// MakeablePredicate
+
+type MakeablePredicate struct {
+	inventory   *Inventory
+	ingredients *IngredientDB
+}
+
+func (mp *MakeablePredicate) Match(candidate *Recipe) (string, bool) {
+	if len(candidate.Components) == 0 || len(mp.inventory.Missing(mp.ingredients, candidate)) > 0 {
+		return "", false
+	}
+
+	var without []string
+	for _, c := range candidate.Components {
+		if mp.ingredients.Optional(c) && !mp.inventory.Has(mp.ingredients, c.Ingredient) {
+			without = append(without, c.Ingredient)
+		}
+	}
+	if len(without) > 0 {
+		return "without " + strings.Join(without, ", "), true
+	}
+	return "all ingredients", true
+}
+
+func (mp *MakeablePredicate) Name() string {
+	return "Makeable"
+}
+
+// NewMakeablePredicate matches recipes whose required components are all in
+// inventory, resolving ingredients with ingredients.
+func NewMakeablePredicate(inventory *Inventory, ingredients *IngredientDB) Predicate {
+	return &MakeablePredicate{
+		inventory:   inventory,
+		ingredients: ingredients,
+	}
+}
@@ -0,0 +1,155 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadInventory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.yaml")
+	writeFile(t, path, `- London Dry Gin
+- 750 ml Campari
+- Peychaud's Bitters
+- ingredient: Lime Juice
+  quantity: 0
+`)
+
+	inv, err := sozzler.LoadInventory(path)
+	require.NoError(t, err)
+	assert.Equal(t, []sozzler.Component{
+		{Ingredient: "London Dry Gin"},
+		{Ingredient: "Campari", Quantity: *must(sozzler.ParseQuantity("750")), Unit: "ml"},
+		{Ingredient: "Peychaud's Bitters"},
+		{Ingredient: "Lime Juice", Quantity: *must(sozzler.ParseQuantity("0"))},
+	}, inv.Items)
+}
+
+func TestLoadInventoryErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.yaml")
+	writeFile(t, path, "- gin\n- 750 ml\n- quantity: 1\n")
+
+	_, err := sozzler.LoadInventory(path)
+	var loadErrs sozzler.LoadErrors
+	require.ErrorAs(t, err, &loadErrs)
+	require.Len(t, loadErrs, 2)
+	assert.Equal(t, 2, loadErrs[0].Line)
+	assert.Equal(t, 3, loadErrs[1].Line)
+}
+
+func TestMakeablePredicate(t *testing.T) {
+	inv := &sozzler.Inventory{Items: []sozzler.Component{
+		{Ingredient: "London Dry Gin"},
+		{Ingredient: "Campari"},
+		{Ingredient: "Sweet Vermouth"},
+		{Ingredient: "Simple Syrup"},
+		{Ingredient: "Lime Juice", Quantity: *must(sozzler.ParseQuantity("0"))},
+		{Ingredient: "Falernum"},
+		{Ingredient: "Orange Bitters"},
+	}}
+
+	testCases := []struct {
+		desc        string
+		ingredients []string
+		wantMatch   string
+		wantOK      bool
+	}{
+		{desc: "category", ingredients: []string{"Gin", "Campari", "Sweet Vermouth"}, wantMatch: "all ingredients", wantOK: true},
+		{desc: "garnish optional", ingredients: []string{"gin", "Orange Peel"}, wantMatch: "without Orange Peel", wantOK: true},
+		{desc: "staples", ingredients: []string{"gin", "water", "crushed ice"}, wantMatch: "all ingredients", wantOK: true},
+		{desc: "unknown ingredient", ingredients: []string{"falernum", "gin"}, wantMatch: "all ingredients", wantOK: true},
+		{desc: "run out", ingredients: []string{"gin", "lime juice", "simple syrup"}},
+		{desc: "more specific", ingredients: []string{"Old Raj Gin"}},
+		{desc: "bitters", ingredients: []string{"gin", "bitters", "orange bitters"}, wantMatch: "all ingredients", wantOK: true},
+		{desc: "other bitters", ingredients: []string{"gin", "Peach Bitters"}},
+		{desc: "unknown bitters", ingredients: []string{"gin", "Celery Bitters"}},
+		{desc: "missing", ingredients: []string{"gin", "Orange Flower Water"}},
+		{desc: "no components"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var r sozzler.Recipe
+			for _, i := range tC.ingredients {
+				r.Components = append(r.Components, sozzler.Component{Ingredient: i})
+			}
+			gotMatch, gotOK := sozzler.NewMakeablePredicate(inv, sozzler.DefaultIngredients()).Match(&r)
+			assert.Equal(t, tC.wantMatch, gotMatch)
+			assert.Equal(t, tC.wantOK, gotOK)
+		})
+	}
+}
+
+func TestMissing(t *testing.T) {
+	inv := &sozzler.Inventory{Items: []sozzler.Component{{Ingredient: "bourbon"}}}
+	r := &sozzler.Recipe{Components: []sozzler.Component{
+		{Ingredient: "Rye"},
+		{Ingredient: "Whiskey"},
+		{Ingredient: "Sugar Cube"},
+		{Ingredient: "Lemon Twist"},
+	}}
+
+	var missing []string
+	for _, c := range inv.Missing(sozzler.DefaultIngredients(), r) {
+		missing = append(missing, c.Ingredient)
+	}
+	assert.Equal(t, []string{"Rye", "Sugar Cube"}, missing)
+}
+
+func TestSuggest(t *testing.T) {
+	recipe := func(name string, rating int, ingredients ...string) *sozzler.Recipe {
+		r := &sozzler.Recipe{Name: name, Rating: rating}
+		for _, i := range ingredients {
+			r.Components = append(r.Components, sozzler.Component{Ingredient: i})
+		}
+		return r
+	}
+	recipes := []*sozzler.Recipe{
+		recipe("Gimlet", 3, "gin", "lime juice", "simple syrup"),
+		recipe("Daiquiri", 5, "light rum", "lime juice", "simple syrup"),
+		recipe("Gin and Tonic", 2, "gin", "tonic water", "lime wedge"),
+		recipe("Negroni", 4, "gin", "campari", "sweet vermouth"),
+		recipe("Martini", 1, "gin"),
+		recipe("Bijou", 4, "gin", "chartreuse", "sweet vermouth"),
+	}
+	inv := &sozzler.Inventory{Items: []sozzler.Component{
+		{Ingredient: "London Dry Gin"},
+		{Ingredient: "Simple Syrup"},
+		{Ingredient: "Sweet Vermouth"},
+	}}
+
+	suggestions := inv.Suggest(sozzler.DefaultIngredients(), recipes, 10)
+
+	type got struct {
+		Ingredient string
+		Recipes    []string
+		Score      int
+	}
+	var gots []got
+	for _, s := range suggestions {
+		g := got{Ingredient: s.Ingredient, Score: s.Score}
+		for _, r := range s.Recipes {
+			g.Recipes = append(g.Recipes, r.Name)
+		}
+		gots = append(gots, g)
+	}
+	assert.Equal(t, []got{
+		{Ingredient: "campari", Recipes: []string{"Negroni"}, Score: 4},
+		{Ingredient: "chartreuse", Recipes: []string{"Bijou"}, Score: 4},
+		{Ingredient: "lime juice", Recipes: []string{"Gimlet"}, Score: 3},
+		{Ingredient: "light rum", Recipes: []string{"Daiquiri"}, Score: 5},
+		{Ingredient: "tonic water", Recipes: []string{"Gin and Tonic"}, Score: 2},
+	}, gots)
+
+	assert.Len(t, inv.Suggest(sozzler.DefaultIngredients(), recipes, 2), 2)
+	assert.Len(t, inv.Items, 3, "inventory is unchanged")
+
+	empty := &sozzler.Inventory{}
+	suggestions = empty.Suggest(sozzler.DefaultIngredients(), recipes[:1], 3)
+	require.Len(t, suggestions, 3)
+	assert.Empty(t, suggestions[0].Recipes, "only helps with the next")
+	assert.Equal(t, "Gimlet", suggestions[2].Recipes[0].Name)
+	assert.Empty(t, empty.Suggest(sozzler.DefaultIngredients(), recipes[:1], 2), "nothing unlocked")
+}
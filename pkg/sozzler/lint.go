@@ -0,0 +1,359 @@
+package sozzler
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is one problem found by a LintRule.
+type Diagnostic struct {
+	Path     string   `json:"path"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	where := d.Path
+	if d.Line > 0 {
+		where = fmt.Sprintf("%s:%d", where, d.Line)
+		if d.Column > 0 {
+			where = fmt.Sprintf("%s:%d", where, d.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", where, d.Severity, d.Message, d.Rule)
+}
+
+// LintRule checks the files of one recipe directory.
+type LintRule struct {
+	Name        string
+	Severity    Severity
+	Description string
+
+	check func(files []*recipeFile, ingredients *IngredientDB, report func(path string, node *yaml.Node, msg string))
+}
+
+// LintRules are every lint rule, in the order they run.
+var LintRules = []LintRule{
+	{
+		Name:        "parse",
+		Severity:    SeverityError,
+		Description: "recipe files must be valid recipe YAML",
+		check:       checkParse,
+	},
+	{
+		Name:        "ingredients",
+		Severity:    SeverityError,
+		Description: "ingredients.yaml must be a valid ingredient list, and categories must be ingredients",
+		check:       checkIngredients,
+	},
+	{
+		Name:        "extension",
+		Severity:    SeverityError,
+		Description: "recipe directories may only contain .yaml files",
+		check:       checkExtension,
+	},
+	{
+		Name:        "missing-name",
+		Severity:    SeverityError,
+		Description: "recipes must have a name",
+		check:       checkMissingName,
+	},
+	{
+		Name:        "duplicate-name",
+		Severity:    SeverityError,
+		Description: "recipe names must be unique, ignoring case, accents, apostrophe styles, and spacing",
+		check:       checkDuplicateName,
+	},
+	{
+		Name:        "name-mismatch",
+		Severity:    SeverityWarning,
+		Description: "recipe file names should match recipe names",
+		check:       checkNameMismatch,
+	},
+	{
+		Name:        "rating-range",
+		Severity:    SeverityError,
+		Description: "ratings must be between 0 and 5",
+		check:       checkRatingRange,
+	},
+	{
+		Name:        "unknown-ingredient",
+		Severity:    SeverityWarning,
+		Description: "ingredients should be in the ingredient database",
+		check:       checkUnknownIngredient,
+	},
+	{
+		Name:        "unknown-method",
+		Severity:    SeverityWarning,
+		Description: "methods should be shaken, stirred, or built",
+		check:       checkUnknownMethod,
+	},
+	{
+		Name:        "zero-quantity",
+		Severity:    SeverityWarning,
+		Description: "unmeasured ingredients should omit quantity and unit rather than use 0",
+		check:       checkZeroQuantity,
+	},
+	{
+		Name:        "unit-without-quantity",
+		Severity:    SeverityError,
+		Description: "components with a unit must have a quantity",
+		check:       checkUnitWithoutQuantity,
+	},
+	{
+		Name:        "unknown-unit",
+		Severity:    SeverityWarning,
+		Description: "units should be known units",
+		check:       checkUnknownUnit,
+	},
+}
+
+// Lint checks every file in recipesDirs against LintRules. Each directory is
+// checked on its own, so a recipe in an overlay directory may share a name with
+// one in the directory it overlays.
+func Lint(recipesDirs ...string) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	for _, dir := range recipesDirs {
+		d, err := lintFS(os.DirFS(dir), ".", dir)
+		if err != nil {
+			return nil, err
+		}
+		diagnostics = append(diagnostics, d...)
+	}
+	return diagnostics, nil
+}
+
+// LintFile checks a single recipe file against LintRules. Rules that compare
+// recipes, like duplicate-name, have nothing to compare it to.
+func LintFile(filename string) ([]Diagnostic, error) {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+	return lintFS(os.DirFS(dir), base, dir)
+}
+
+// LintFS checks every file under root in fsys against LintRules.
+func LintFS(fsys fs.FS, root string) ([]Diagnostic, error) {
+	return lintFS(fsys, root, "")
+}
+
+func lintFS(fsys fs.FS, root string, dir string) ([]Diagnostic, error) {
+	ingredients := DefaultIngredients()
+	// problems loading it are kept in ingredients, for checkIngredients
+	_ = loadIngredients(ingredients, fsys, root, dir)
+
+	var files []*recipeFile
+	err := walkRecipes(fsys, root, dir, func(rf *recipeFile) error {
+		files = append(files, rf)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []Diagnostic
+	for _, rule := range LintRules {
+		rule.check(files, ingredients, func(path string, node *yaml.Node, msg string) {
+			d := Diagnostic{
+				Path:     path,
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				Message:  msg,
+			}
+			if node != nil {
+				d.Line, d.Column = node.Line, node.Column
+			}
+			diagnostics = append(diagnostics, d)
+		})
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		di, dj := diagnostics[i], diagnostics[j]
+		if di.Path != dj.Path {
+			return di.Path < dj.Path
+		}
+		return di.Line < dj.Line
+	})
+
+	return diagnostics, nil
+}
+
+func checkParse(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	for _, f := range files {
+		for _, e := range f.errs {
+			msg := e.Err.Error()
+			if e.Field != "" {
+				msg = e.Field + ": " + msg
+			}
+			report(f.path, &yaml.Node{Line: e.Line, Column: e.Column}, msg)
+		}
+	}
+}
+
+// checkIngredients reports the problems found loading the directory's
+// IngredientsFile into ingredients.
+func checkIngredients(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	for _, e := range ingredients.loadErrs {
+		report(e.Path, &yaml.Node{Line: e.Line, Column: e.Column}, e.Err.Error())
+	}
+}
+
+func checkExtension(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	for _, f := range files {
+		if filepath.Ext(f.path) != ".yaml" {
+			report(f.path, nil, fmt.Sprintf("%q is not a .yaml file", filepath.Base(f.path)))
+		}
+	}
+}
+
+func checkMissingName(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	for _, f := range files {
+		if f.recipe != nil && strings.TrimSpace(f.recipe.Name) == "" {
+			report(f.path, field(f.doc, "name"), "recipe has no name")
+		}
+	}
+}
+
+func checkDuplicateName(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	seen := make(map[string]string)
+	for _, f := range files {
+		if f.recipe == nil || f.recipe.Name == "" {
+			continue
+		}
+		name := foldName(f.recipe.Name)
+		if first, ok := seen[name]; ok {
+			report(f.path, field(f.doc, "name"), fmt.Sprintf("recipe %q is also defined in %s", f.recipe.Name, first))
+			continue
+		}
+		seen[name] = f.path
+	}
+}
+
+func checkNameMismatch(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	for _, f := range files {
+		// files without a .yaml extension are reported by checkExtension
+		if f.recipe == nil || f.recipe.Name == "" || filepath.Ext(f.path) != ".yaml" {
+			continue
+		}
+		base, want := filepath.Base(f.path), RecipeFilename(f.recipe.Name)
+		if base != want {
+			report(f.path, field(f.doc, "name"), fmt.Sprintf("recipe %q should be in file %q", f.recipe.Name, want))
+		}
+	}
+}
+
+func checkRatingRange(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	for _, f := range files {
+		if f.recipe != nil && (f.recipe.Rating < 0 || f.recipe.Rating > 5) {
+			report(f.path, field(f.doc, "rating"), fmt.Sprintf("rating %d is not between 0 and 5", f.recipe.Rating))
+		}
+	}
+}
+
+func checkUnknownIngredient(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	forEachComponent(files, func(f *recipeFile, c Component, node *yaml.Node) {
+		if _, ok := ingredients.Resolve(c.Ingredient); c.Ingredient != "" && !ok {
+			report(f.path, field(node, "ingredient"), fmt.Sprintf("%q is not in the ingredient database", c.Ingredient))
+		}
+	})
+}
+
+func checkUnknownMethod(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	for _, f := range files {
+		if f.recipe == nil || f.recipe.Method == "" {
+			continue
+		}
+		if _, err := ParseMethod(string(f.recipe.Method)); err != nil {
+			report(f.path, field(f.doc, "method"), err.Error())
+		}
+	}
+}
+
+func checkZeroQuantity(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	forEachComponent(files, func(f *recipeFile, c Component, node *yaml.Node) {
+		if c.Quantity.IsZeroAmount() {
+			report(f.path, field(node, "quantity"), fmt.Sprintf("%q has quantity %q", c.Ingredient, c.Quantity.text()))
+		}
+	})
+}
+
+func checkUnitWithoutQuantity(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	forEachComponent(files, func(f *recipeFile, c Component, node *yaml.Node) {
+		if c.Unit != "" && c.Quantity.IsZero() {
+			report(f.path, field(node, "unit"), fmt.Sprintf("%q has unit %q but no quantity", c.Ingredient, c.Unit))
+		}
+	})
+}
+
+func checkUnknownUnit(files []*recipeFile, ingredients *IngredientDB, report func(string, *yaml.Node, string)) {
+	forEachComponent(files, func(f *recipeFile, c Component, node *yaml.Node) {
+		if _, ok := LookupUnit(c.Unit); c.Unit != "" && !ok {
+			report(f.path, field(node, "unit"), fmt.Sprintf("%q has unknown unit %q", c.Ingredient, c.Unit))
+		}
+	})
+}
+
+// forEachComponent calls fn with every component of every decoded recipe in
+// files, along with the yaml node it was decoded from.
+func forEachComponent(files []*recipeFile, fn func(*recipeFile, Component, *yaml.Node)) {
+	for _, f := range files {
+		if f.recipe == nil {
+			continue
+		}
+		nodes := field(f.doc, "components")
+		for i, c := range f.recipe.Components {
+			var node *yaml.Node
+			if nodes != nil && i < len(nodes.Content) {
+				node = nodes.Content[i]
+			}
+			fn(f, c, node)
+		}
+	}
+}
+
+// field returns the value of key in the mapping node, or nil.
+func field(node *yaml.Node, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Aviation.yaml": {Data: []byte(`name: Aviation
+components:
+  - ingredient: gin
+    quantity: '2/1'
+    unit: oz
+  - ingredient: lemon peel
+    quantity: '0/1'
+    unit: ''
+  - ingredient: orange bitters
+    quantity: 0-1
+    unit: dash
+rating: 4
+`)},
+		"tiki/Aviation 2.yaml":  {Data: []byte("name: aviation\nrating: 7\n")},
+		"Vieux Carré.yaml":      {Data: []byte("name: Vieux Carré\n")},
+		"tiki/Vieux Carre.yaml": {Data: []byte("name: Vieux Carre\n")},
+		"Broken.yaml":           {Data: []byte("name: [Broken\n")},
+		"README.md":             {Data: []byte("name: README\n")},
+		"Zombie.yaml": {Data: []byte(`name: Zombie
+method: blended
+components:
+  - ingredient: rum
+    unit: glugs
+`)},
+	}
+
+	diagnostics, err := sozzler.LintFS(fsys, ".")
+	require.NoError(t, err)
+
+	type got struct {
+		Path string
+		Line int
+		Rule string
+	}
+	var gots []got
+	for _, d := range diagnostics {
+		gots = append(gots, got{d.Path, d.Line, d.Rule})
+	}
+
+	assert.Equal(t, []got{
+		{"Aviation.yaml", 7, "zero-quantity"},
+		{"Broken.yaml", 1, "parse"},
+		{"README.md", 0, "extension"},
+		{"Zombie.yaml", 2, "unknown-method"},
+		{"Zombie.yaml", 5, "unit-without-quantity"},
+		{"Zombie.yaml", 5, "unknown-unit"},
+		{"tiki/Aviation 2.yaml", 1, "duplicate-name"},
+		{"tiki/Aviation 2.yaml", 1, "name-mismatch"},
+		{"tiki/Aviation 2.yaml", 2, "rating-range"},
+		{"tiki/Vieux Carre.yaml", 1, "duplicate-name"},
+	}, gots)
+}
+
+func TestLintSeverity(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Aviation.yaml": {Data: []byte("name: Aviation\nrating: -1\n")},
+	}
+
+	diagnostics, err := sozzler.LintFS(fsys, ".")
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, sozzler.SeverityError, diagnostics[0].Severity)
+	assert.Equal(t, "Aviation.yaml:2:9: error: rating -1 is not between 0 and 5 (rating-range)", diagnostics[0].String())
+}
+
+func TestLintFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "Aviation.yaml")
+	writeFile(t, filename, "name: Aviation\nrating: 9\n")
+	writeFile(t, filepath.Join(dir, "Broken.yaml"), "name: [Broken\n")
+
+	diagnostics, err := sozzler.LintFile(filename)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, filename, diagnostics[0].Path)
+	assert.Equal(t, "rating-range", diagnostics[0].Rule)
+}
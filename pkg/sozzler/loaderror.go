@@ -0,0 +1,179 @@
+package sozzler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadError describes a recipe file that couldn't be loaded. Line, Column, and
+// Field are zero when the problem can't be pinned to a spot in the file.
+type LoadError struct {
+	Path   string
+	Line   int
+	Column int
+	Field  string
+	Err    error
+}
+
+func (e *LoadError) Error() string {
+	where := e.Path
+	if e.Line > 0 {
+		where += ":" + strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			where += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	if e.Field != "" {
+		where += ": " + e.Field
+	}
+	return where + ": " + e.Err.Error()
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// LoadErrors is every LoadError from a load.
+type LoadErrors []*LoadError
+
+func (e LoadErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d recipe errors:\n%s", len(e), strings.Join(msgs, "\n"))
+}
+
+func (e LoadErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+var lineRe = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// decodeRecipe decodes a recipe and the yaml document it came from, reporting
+// every problem yaml finds with as much location detail as it can recover.
+func decodeRecipe(path string, r io.Reader) (*Recipe, *yaml.Node, LoadErrors) {
+	var recipe Recipe
+	doc, errs := decodeYAML(path, r, &recipe)
+	if errs != nil {
+		return nil, nil, errs
+	}
+	return &recipe, doc, nil
+}
+
+// decodeYAML decodes the yaml document in r into v, returning the document.
+func decodeYAML(path string, r io.Reader, v interface{}) (*yaml.Node, LoadErrors) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, LoadErrors{{Path: path, Err: errors.New("empty file")}}
+		}
+		loadErr := &LoadError{Path: path, Err: err}
+		if m := lineRe.FindStringSubmatch(err.Error()); m != nil {
+			loadErr.Line, _ = strconv.Atoi(m[1])
+			loadErr.Err = errors.New(m[2])
+		}
+		return nil, LoadErrors{loadErr}
+	}
+
+	if err := doc.Decode(v); err != nil {
+		return nil, typeErrors(path, &doc, err)
+	}
+	return &doc, nil
+}
+
+// decodeStrictYAML is decodeYAML, also failing on keys v has no field for, so
+// a misspelled key isn't silently ignored.
+func decodeStrictYAML(path string, r io.Reader, v interface{}) (*yaml.Node, LoadErrors) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, LoadErrors{{Path: path, Err: err}}
+	}
+	doc, errs := decodeYAML(path, bytes.NewReader(src), v)
+	if errs != nil {
+		return nil, errs
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(src))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil {
+		return nil, typeErrors(path, doc, err)
+	}
+	return doc, nil
+}
+
+// typeErrors turns the errors from decoding doc into LoadErrors, one for each
+// problem yaml found, located in doc where it can be.
+func typeErrors(path string, doc *yaml.Node, err error) LoadErrors {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return LoadErrors{{Path: path, Err: err}}
+	}
+
+	var errs LoadErrors
+	for _, msg := range typeErr.Errors {
+		loadErr := &LoadError{Path: path, Err: errors.New(msg)}
+		if m := lineRe.FindStringSubmatch(msg); m != nil {
+			loadErr.Line, _ = strconv.Atoi(m[1])
+			loadErr.Err = errors.New(m[2])
+			loadErr.Column, loadErr.Field = locate(doc, loadErr.Line, "")
+		}
+		errs = append(errs, loadErr)
+	}
+	return errs
+}
+
+// locate finds the first field in node on line, returning its column and a path
+// like "components[1].quantity".
+func locate(node *yaml.Node, line int, path string) (int, string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			if col, field := locate(n, line, path); col > 0 {
+				return col, field
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field := key.Value
+			if path != "" {
+				field = path + "." + key.Value
+			}
+			if key.Line == line {
+				if value.Kind == yaml.ScalarNode && value.Line == line {
+					return value.Column, field
+				}
+				return key.Column, field
+			}
+			if col, f := locate(value, line, field); col > 0 {
+				return col, f
+			}
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			if col, field := locate(n, line, fmt.Sprintf("%s[%d]", path, i)); col > 0 {
+				return col, field
+			}
+		}
+	case yaml.ScalarNode:
+		if node.Line == line {
+			return node.Column, path
+		}
+	}
+	return 0, ""
+}
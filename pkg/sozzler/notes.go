@@ -0,0 +1,327 @@
+package sozzler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// snippetWords is how many words of context a snippet shows on each side of
+// a match.
+const snippetWords = 5
+
+// TextSpan is the byte range [Start, End) of some text.
+type TextSpan struct {
+	Start, End int
+}
+
+// Snippet is an excerpt of a recipe's notes, and the spans of it that matched.
+type Snippet struct {
+	Text       string
+	Highlights []TextSpan
+}
+
+// SnippetPredicate is a ScoredPredicate whose matches are excerpts of longer
+// text, with the matching parts highlighted.
+type SnippetPredicate interface {
+	ScoredPredicate
+	Snippet(*Recipe) (Snippet, float64, bool)
+}
+
+// textToken is a word of some text, stemmed, and where it is in the text.
+type textToken struct {
+	stem string
+	span TextSpan
+}
+
+// TextIndex is an inverted index of the words in recipes' notes, for finding
+// recipes by words and phrases in their notes.
+type TextIndex struct {
+	tokens map[*Recipe][]textToken
+	// postings are the positions of each stem in the tokens of each recipe.
+	postings map[string]map[*Recipe][]int
+}
+
+// NewTextIndex indexes the notes of recipes.
+func NewTextIndex(recipes []*Recipe) *TextIndex {
+	ix := &TextIndex{
+		tokens:   make(map[*Recipe][]textToken),
+		postings: make(map[string]map[*Recipe][]int),
+	}
+	for _, r := range recipes {
+		tokens := tokenize(r.Notes)
+		ix.tokens[r] = tokens
+		for i, t := range tokens {
+			if ix.postings[t.stem] == nil {
+				ix.postings[t.stem] = make(map[*Recipe][]int)
+			}
+			ix.postings[t.stem][r] = append(ix.postings[t.stem][r], i)
+		}
+	}
+	return ix
+}
+
+// phrase returns the positions in each recipe's tokens where stems occur in
+// order.
+func (ix *TextIndex) phrase(stems []string) map[*Recipe][]int {
+	found := make(map[*Recipe][]int)
+	for r, positions := range ix.postings[stems[0]] {
+		for _, pos := range positions {
+			if ix.phraseAt(r, pos, stems) {
+				found[r] = append(found[r], pos)
+			}
+		}
+	}
+	return found
+}
+
+// phraseAt reports whether stems occur in order from pos in r's tokens.
+func (ix *TextIndex) phraseAt(r *Recipe, pos int, stems []string) bool {
+	tokens := ix.tokens[r]
+	if pos+len(stems) > len(tokens) {
+		return false
+	}
+	for i, stem := range stems {
+		if tokens[pos+i].stem != stem {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize splits text into words of letters and digits, and stems them.
+// Apostrophes inside a word are part of it.
+func tokenize(text string) []textToken {
+	var tokens []textToken
+	start := -1
+	for i, r := range text + " " {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		if !inWord && start >= 0 && isApostrophe(r) {
+			next, _ := utf8.DecodeRuneInString(text[min(i+utf8.RuneLen(r), len(text)):])
+			inWord = unicode.IsLetter(next)
+		}
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			if stem := stemWord(text[start:i]); stem != "" {
+				tokens = append(tokens, textToken{stem: stem, span: TextSpan{start, i}})
+			}
+			start = -1
+		}
+	}
+	return tokens
+}
+
+// stemWord folds word like foldName, and strips common English endings, so
+// "pairs", "paired", and "pairing" all stem to "pair". Stems aren't always
+// words: "shake" and "shaking" stem to "shak".
+func stemWord(word string) string {
+	word = strings.ReplaceAll(foldName(word), "'", "")
+	if strings.HasSuffix(word, "s") && len(word) > 3 {
+		switch {
+		case strings.HasSuffix(word, "ies") && len(word) > 4:
+			word = strings.TrimSuffix(word, "ies") + "y"
+		case strings.HasSuffix(word, "sses"):
+			word = strings.TrimSuffix(word, "es")
+		case !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+			word = strings.TrimSuffix(word, "s")
+		}
+	}
+	for _, suffix := range []string{"ing", "ed"} {
+		if stem := strings.TrimSuffix(word, suffix); stem != word && len(stem) >= 3 && strings.IndexAny(stem, "aeiouy") >= 0 {
+			word = stem
+			// stirring, stir
+			if n := len(stem); stem[n-1] == stem[n-2] && !strings.ContainsRune("aeiouylsz", rune(stem[n-1])) {
+				word = stem[:n-1]
+			}
+			break
+		}
+	}
+	if stem := strings.TrimSuffix(word, "e"); len(stem) >= 3 && !strings.HasSuffix(stem, "e") {
+		word = stem
+	}
+	return word
+}
+
+// isApostrophe reports whether r is a straight or curly apostrophe.
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// parseTextQuery splits a text query into phrases of stems. Words in quotes
+// are a phrase, and every other word is a phrase of its own.
+func parseTextQuery(query string) ([][]string, error) {
+	var phrases [][]string
+	add := func(text string) {
+		for _, t := range tokenize(text) {
+			phrases = append(phrases, []string{t.stem})
+		}
+	}
+	rest := query
+	for {
+		open := strings.IndexByte(rest, '"')
+		if open < 0 {
+			add(rest)
+			break
+		}
+		add(rest[:open])
+		end := strings.IndexByte(rest[open+1:], '"')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid text search %q: unterminated quote", query)
+		}
+		var stems []string
+		for _, t := range tokenize(rest[open+1 : open+1+end]) {
+			stems = append(stems, t.stem)
+		}
+		if len(stems) > 0 {
+			phrases = append(phrases, stems)
+		}
+		rest = rest[open+1+end+1:]
+	}
+	if len(phrases) == 0 {
+		return nil, fmt.Errorf("invalid text search %q: no words to search for", query)
+	}
+	return phrases, nil
+}
+
+// mergeRanges sorts ranges, and merges those that overlap, as repeated or
+// overlapping phrases match the same tokens.
+func mergeRanges(ranges []tokenRange) []tokenRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].pos != ranges[j].pos {
+			return ranges[i].pos < ranges[j].pos
+		}
+		return ranges[i].n > ranges[j].n
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.pos < last.pos+last.n {
+			last.n = max(last.n, r.pos+r.n-last.pos)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// NotesPredicate
+
+type NotesPredicate struct {
+	index *TextIndex
+	// found are the spans of the tokens of each matching recipe that matched.
+	found map[*Recipe][]tokenRange
+	terms int
+}
+
+// tokenRange is n tokens from pos.
+type tokenRange struct {
+	pos, n int
+}
+
+func (np *NotesPredicate) Match(candidate *Recipe) (string, bool) {
+	snippet, _, ok := np.Snippet(candidate)
+	return snippet.Text, ok
+}
+
+// Score scores a match SubstringScore for each word or phrase searched for,
+// as they're somewhere in the notes.
+func (np *NotesPredicate) Score(candidate *Recipe) (string, float64, bool) {
+	snippet, score, ok := np.Snippet(candidate)
+	return snippet.Text, score, ok
+}
+
+// Snippet excerpts candidate's notes around their first match, with
+// snippetWords words either side, and highlights every match in it.
+func (np *NotesPredicate) Snippet(candidate *Recipe) (Snippet, float64, bool) {
+	found, ok := np.found[candidate]
+	if !ok {
+		return Snippet{}, 0, false
+	}
+	tokens := np.index.tokens[candidate]
+	notes := candidate.Notes
+
+	first := found[0]
+	from := max(first.pos-snippetWords, 0)
+	to := min(first.pos+first.n+snippetWords, len(tokens)) - 1
+
+	start, end := tokens[from].span.Start, tokens[to].span.End
+	if from == 0 {
+		start = len(notes) - len(strings.TrimLeftFunc(notes, unicode.IsSpace))
+	}
+	if to == len(tokens)-1 {
+		end = len(strings.TrimRightFunc(notes, unicode.IsSpace))
+	}
+
+	// the excerpt, with runs of spaces and newlines collapsed, and where each
+	// byte of the notes ended up in it
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	at := make(map[int]int)
+	space := false
+	for i, r := range notes[start:end] {
+		at[start+i] = b.Len()
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	at[end] = b.Len()
+	if to < len(tokens)-1 {
+		b.WriteString("…")
+	}
+
+	var highlights []TextSpan
+	for _, f := range found {
+		if f.pos >= from && f.pos+f.n-1 <= to {
+			highlights = append(highlights, TextSpan{at[tokens[f.pos].span.Start], at[tokens[f.pos+f.n-1].span.End]})
+		}
+	}
+
+	return Snippet{Text: b.String(), Highlights: highlights}, float64(np.terms) * SubstringScore, true
+}
+
+func (np *NotesPredicate) Name() string {
+	return "Notes"
+}
+
+// NewNotesPredicate matches recipes in index whose notes have every word of
+// query. Words are matched by their stems, so "pairing" matches "pairs", and
+// words in quotes, like "dark chocolate", must be next to each other.
+func NewNotesPredicate(index *TextIndex, query string) (Predicate, error) {
+	phrases, err := parseTextQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var found map[*Recipe][]tokenRange
+	for i, stems := range phrases {
+		matches := make(map[*Recipe][]tokenRange)
+		for r, positions := range index.phrase(stems) {
+			if i > 0 && found[r] == nil {
+				continue
+			}
+			ranges := append([]tokenRange(nil), found[r]...)
+			for _, pos := range positions {
+				ranges = append(ranges, tokenRange{pos, len(stems)})
+			}
+			matches[r] = ranges
+		}
+		found = matches
+	}
+	for r, ranges := range found {
+		found[r] = mergeRanges(ranges)
+	}
+
+	return &NotesPredicate{index: index, found: found, terms: len(phrases)}, nil
+}
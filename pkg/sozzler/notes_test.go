@@ -0,0 +1,137 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotesPredicate(t *testing.T) {
+	testCases := []struct {
+		query string
+		notes string
+		want  bool
+	}{
+		{"chocolate", "Strain into a coupe. Pairs well with dark chocolate.", true},
+		{"CHOCOLATE", "Serve after dinner with chocolate, dark or milk.", true},
+		{`"dark chocolate"`, "Strain into a coupe. Pairs well with dark chocolate.", true},
+		{`"dark chocolate"`, "Serve after dinner with chocolate, dark or milk.", false},
+		{`"milk chocolate"`, "Serve after dinner with chocolate, dark or milk.", false},
+		{"dark chocolate", "Serve after dinner with chocolate, dark or milk.", true},
+		{"pairing", "Strain into a coupe. Pairs well with dark chocolate.", true},
+		{"paired", "Strain into a coupe. Pairs well with dark chocolate.", true},
+		{"stir", "Stir with ice in a rocks glass. Garnish with an orange peel.", true},
+		{"stir", "Stirring is traditional, though some shake it.", true},
+		{"shaking", "Shake hard with ice.\nStrain into a coupe.", true},
+		{"glasses", "Stir with ice in a rocks glass. Garnish with an orange peel.", true},
+		{"cafe", "Flame the brandy in a bowl, and ladle it over the café.", true},
+		{"flaming bowls", "Flame the brandy in a bowl, and ladle it over the café.", true},
+		{`"orange peel" rocks`, "Stir with ice in a rocks glass. Garnish with an orange peel.", true},
+		{`"orange peel" coupe`, "Stir with ice in a rocks glass. Garnish with an orange peel.", false},
+		{"gin", "Stir with ice in a rocks glass. Garnish with an orange peel.", false},
+		{"stir", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query+" "+tc.notes, func(t *testing.T) {
+			recipe := &sozzler.Recipe{Name: "Test", Notes: tc.notes}
+			p, err := sozzler.NewNotesPredicate(sozzler.NewTextIndex([]*sozzler.Recipe{recipe}), tc.query)
+			require.NoError(t, err)
+
+			_, ok := p.Match(recipe)
+			assert.Equal(t, tc.want, ok)
+		})
+	}
+}
+
+func TestNotesPredicateErrors(t *testing.T) {
+	testCases := []struct {
+		query string
+		want  string
+	}{
+		{`"dark chocolate`, `invalid text search "\"dark chocolate": unterminated quote`},
+		{"...", `invalid text search "...": no words to search for`},
+		{`""`, `invalid text search "\"\"": no words to search for`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			_, err := sozzler.NewNotesPredicate(sozzler.NewTextIndex(nil), tc.query)
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestNotesSnippet(t *testing.T) {
+	testCases := []struct {
+		query      string
+		notes      string
+		want       string
+		highlights []string
+	}{
+		{
+			query:      `"dark chocolate"`,
+			notes:      "Shake hard with ice.\nStrain into a coupe. Pairs well with dark chocolate.",
+			want:       "…a coupe. Pairs well with dark chocolate.",
+			highlights: []string{"dark chocolate"},
+		},
+		{
+			query:      "ice coupe",
+			notes:      "Shake hard with ice.\nStrain into a coupe. Pairs well with dark chocolate.",
+			want:       "Shake hard with ice. Strain into a coupe. Pairs…",
+			highlights: []string{"ice", "coupe"},
+		},
+		{
+			query:      "stir",
+			notes:      "Stirring is traditional, though some shake it. Serve after dinner with chocolate, dark or milk.",
+			want:       "Stirring is traditional, though some shake…",
+			highlights: []string{"Stirring"},
+		},
+		{
+			query:      "chocolate chocolate",
+			notes:      "Shake hard with ice.\nStrain into a coupe. Pairs well with dark chocolate.",
+			want:       "…coupe. Pairs well with dark chocolate.",
+			highlights: []string{"chocolate"},
+		},
+		{
+			query:      `"dark chocolate" chocolate "well with dark"`,
+			notes:      "Shake hard with ice.\nStrain into a coupe. Pairs well with dark chocolate.",
+			want:       "…Strain into a coupe. Pairs well with dark chocolate.",
+			highlights: []string{"well with dark chocolate"},
+		},
+		{
+			query:      `"chocolate dark" dark`,
+			notes:      "Stirring is traditional, though some shake it. Serve after dinner with chocolate, dark or milk.",
+			want:       "…it. Serve after dinner with chocolate, dark or milk.",
+			highlights: []string{"chocolate, dark"},
+		},
+		{
+			query:      "cafe",
+			notes:      "Flame the brandy in a bowl, and ladle it over the café.",
+			want:       "…and ladle it over the café.",
+			highlights: []string{"café"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			recipe := &sozzler.Recipe{Name: "Test", Notes: tc.notes}
+			p, err := sozzler.NewNotesPredicate(sozzler.NewTextIndex([]*sozzler.Recipe{recipe}), tc.query)
+			require.NoError(t, err)
+
+			results := (&sozzler.RecipeCatalog{Recipes: []*sozzler.Recipe{recipe}}).Search([]sozzler.Predicate{p})
+			require.Len(t, results, 1)
+			require.Len(t, results[0].Matches, 1)
+			m := results[0].Matches[0]
+			assert.Equal(t, "Notes", m.Predicate.Name())
+			assert.Equal(t, tc.want, m.Match)
+			var highlights []string
+			for _, h := range m.Highlights {
+				highlights = append(highlights, m.Match[h.Start:h.End])
+			}
+			assert.Equal(t, tc.highlights, highlights)
+		})
+	}
+}
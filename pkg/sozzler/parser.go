@@ -16,69 +16,31 @@ type RecipeParser struct{}
 var ErrParseError = errors.New("Parse Error")
 
 func (rp *RecipeParser) ParseComponent(r io.Reader) (*Component, error) {
-	var s scanner.Scanner
+	line, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	q, rest, err := quantityPrefix(string(line))
+	if err != nil {
+		return nil, ErrParseError
+	}
 
-	var numerator, denominator int
+	var s scanner.Scanner
 	var words []string
-	var slashed bool
 
-	s.Init(r)
+	s.Init(strings.NewReader(rest))
 	s.Mode &^= scanner.ScanFloats // otherwise, 1egg is "1e" "gg"
 	s.Mode |= scanner.ScanInts    // re-enable Int scanning disabled line above
 	for tok := s.Scan(); tok != scanner.EOF && s.ErrorCount == 0; tok = s.Scan() {
-		text := s.TokenText()
-
-		if text == "/" {
-			if slashed {
-				return nil, ErrParseError
-			}
-			slashed = true
-			continue
-		}
-
-		n, err := strconv.Atoi(text)
-		if err != nil {
-			// couldn't parse a digit, so it's a word
-			words = append(words, text)
-			continue
-		}
-		if len(words) != 0 {
-			// it's an int, but we've already added a word, so input is like "1foo2"
-			return nil, ErrParseError
-		}
-		if numerator == 0 {
-			// numerator hasn't been set yet (probably)
-			numerator = n
-			continue
-		}
-		denominator = n
-		continue
+		words = append(words, s.TokenText())
 	}
 
-	var q *Quantity
-	var err error
-	if denominator == 0 {
-		if numerator == 0 {
-			// ""
-			q = &Quantity{}
-		} else {
-			// e.g. "1 foo"
-			q, err = ParseQuantity(fmt.Sprint(numerator))
-		}
-	} else {
-		q, err = ParseQuantity(fmt.Sprintf("%d/%d", numerator, denominator))
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	c := Component{Quantity: *q}
+	c := Component{Quantity: q}
 
-	if len(words) > 0 {
-		if _, ok := knownUnits[words[0]]; ok {
-			c.Unit = words[0]
-			words = words[1:]
-		}
+	if u, n := unitPrefix(words); u != nil {
+		c.Unit = u.Name
+		words = words[n:]
 	}
 
 	c.Ingredient = strings.Join(words, " ")
@@ -91,6 +53,55 @@ func (rp *RecipeParser) ParseComponent(r io.Reader) (*Component, error) {
 	return &c, nil
 }
 
+// quantityPrefix splits the quantity, like "1 1/2", "1.5", "½", or "1-2", from
+// the start of line, returning it and the rest of the line. Numbers that can't
+// be part of the quantity, like the 151 of "1 151 rum", are left in the rest.
+func quantityPrefix(line string) (Quantity, string, error) {
+	line = strings.TrimSpace(line)
+	end := strings.IndexFunc(line, func(r rune) bool {
+		return !strings.ContainsRune("0123456789 ./-–⁄", r) && vulgarFractions[r] == nil
+	})
+	if end < 0 {
+		end = len(line)
+	}
+
+	fields := strings.Fields(line[:end])
+	for n := len(fields); n > 0; n-- {
+		q, err := parseQuantity(strings.Join(fields[:n], " "))
+		if err != nil {
+			if _, err := strconv.Atoi(fields[n-1]); err != nil {
+				// only whole numbers can start an ingredient
+				break
+			}
+			continue
+		}
+		rest := append(fields[n:], line[end:])
+		return q, strings.Join(rest, " "), nil
+	}
+	if len(fields) > 0 {
+		return Quantity{}, "", fmt.Errorf("invalid quantity %q", line[:end])
+	}
+	return Quantity{}, line, nil
+}
+
+// unitPrefix finds the longest run of words at the start of words that spells a
+// unit, like "fl oz" or "oz.", returning the unit and how many words it used.
+func unitPrefix(words []string) (*Unit, int) {
+	for n := min(len(words), 4); n > 0; n-- {
+		var name string
+		for i, w := range words[:n] {
+			if i > 0 && w != "." {
+				name += " "
+			}
+			name += w
+		}
+		if u, ok := LookupUnit(name); ok {
+			return u, n
+		}
+	}
+	return nil, 0
+}
+
 func (rp *RecipeParser) Parse(r io.Reader) (*Recipe, error) {
 	scanner := bufio.NewScanner(r)
 
@@ -28,8 +28,33 @@ func TestComponents(t *testing.T) {
 			wantComponent: component("hard boiled egg", "1/8", ""),
 		},
 		{
+			// aliases resolve to the unit's name
 			given:         "1/8 ounce hard boiled egg",
-			wantComponent: component("hard boiled egg", "1/8", "ounce"),
+			wantComponent: component("hard boiled egg", "1/8", "oz"),
+		},
+		{
+			given:         "2 ounces gin",
+			wantComponent: component("gin", "2", "oz"),
+		},
+		{
+			given:         "2 oz. gin",
+			wantComponent: component("gin", "2", "oz"),
+		},
+		{
+			given:         "2 fl oz gin",
+			wantComponent: component("gin", "2", "oz"),
+		},
+		{
+			given:         "3 solid dashes bitters",
+			wantComponent: component("bitters", "3", "dash"),
+		},
+		{
+			given:         "1 Tbsp turbinado sugar",
+			wantComponent: component("turbinado sugar", "1", "tbsp"),
+		},
+		{
+			given:         "1 TSP turbinado sugar",
+			wantComponent: component("turbinado sugar", "1", "tsp"),
 		},
 		{
 			given:         "1/8 oz hard boiled egg",
@@ -101,6 +126,38 @@ func TestComponents(t *testing.T) {
 			given:         "2/1 poached eggs",
 			wantComponent: component("poached eggs", "2/1", ""),
 		},
+		{
+			given:         "1.5 oz egg",
+			wantComponent: component("egg", "1.5", "oz"),
+		},
+		{
+			given:         "1 1/2 oz gin",
+			wantComponent: component("gin", "3/2", "oz"),
+		},
+		{
+			given:         "½ oz lime juice",
+			wantComponent: component("lime juice", "1/2", "oz"),
+		},
+		{
+			given:         "1½ oz gin",
+			wantComponent: component("gin", "3/2", "oz"),
+		},
+		{
+			given:         "1 ½ oz gin",
+			wantComponent: component("gin", "3/2", "oz"),
+		},
+		{
+			given:         "1-2 dashes bitters",
+			wantComponent: component("bitters", "1-2", "dash"),
+		},
+		{
+			given:         "1 – 1 1/2 oz lime juice",
+			wantComponent: component("lime juice", "1-3/2", "oz"),
+		},
+		{
+			given:         "1 151 rum",
+			wantComponent: component("151 rum", "1", ""),
+		},
 		{
 			given:         "poached egg",
 			wantComponent: component("poached egg", "", ""),
@@ -147,7 +204,11 @@ func TestComponents(t *testing.T) {
 			wantErr: sozzler.ErrParseError,
 		},
 		{
-			given:   "1.5 oz egg",
+			given:   "1 1/2/3 oz egg",
+			wantErr: sozzler.ErrParseError,
+		},
+		{
+			given:   "2-1 dashes bitters",
 			wantErr: sozzler.ErrParseError,
 		},
 	}
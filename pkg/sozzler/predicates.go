@@ -1,6 +1,9 @@
 package sozzler
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 type Predicate interface {
 	Match(*Recipe) (string, bool)
@@ -11,21 +14,35 @@ type Predicate interface {
 
 type IngredientPredicate struct {
 	ingredient string
+	// fuzzy matches ingredients with a typo or two, too.
+	fuzzy bool
 }
 
 func (ip *IngredientPredicate) Match(candidate *Recipe) (string, bool) {
+	match, _, ok := ip.Score(candidate)
+	return match, ok
+}
+
+// Score scores the ingredient that best matches, the first of them on a tie.
+func (ip *IngredientPredicate) Score(candidate *Recipe) (string, float64, bool) {
+	best, match := 0.0, ""
 	for _, c := range candidate.Components {
-		if strings.Contains(strings.ToLower(c.Ingredient), ip.ingredient) {
-			return c.Ingredient, true
+		if score := scoreText(ip.ingredient, strings.ToLower(c.Ingredient), ip.fuzzy); score > best {
+			best, match = score, c.Ingredient
 		}
 	}
-	return "", false
+	return match, best, best > 0
 }
 
 func (ip *IngredientPredicate) Name() string {
 	return "Ingredient"
 }
 
+// Fuzzy returns ip, also matching ingredients with a typo or two.
+func (ip *IngredientPredicate) Fuzzy() Predicate {
+	return &IngredientPredicate{ingredient: ip.ingredient, fuzzy: true}
+}
+
 func NewIngredientPredicate(ingredient string) Predicate {
 	return &IngredientPredicate{
 		ingredient: strings.TrimSpace(strings.ToLower(ingredient)),
@@ -36,19 +53,34 @@ func NewIngredientPredicate(ingredient string) Predicate {
 
 type NamePredicate struct {
 	name string
+	// fuzzy matches names with a typo or two, too.
+	fuzzy bool
 }
 
 func (ip *NamePredicate) Match(candidate *Recipe) (string, bool) {
-	if strings.Contains(strings.ToLower(candidate.Name), ip.name) {
-		return candidate.Name, true
+	match, _, ok := ip.Score(candidate)
+	return match, ok
+}
+
+// Score weighs name matches by NameWeight, as a recipe's name says more about
+// it than any one ingredient.
+func (ip *NamePredicate) Score(candidate *Recipe) (string, float64, bool) {
+	score := scoreText(ip.name, strings.ToLower(candidate.Name), ip.fuzzy)
+	if score == 0 {
+		return "", 0, false
 	}
-	return "", false
+	return candidate.Name, score * NameWeight, true
 }
 
 func (ip *NamePredicate) Name() string {
 	return "Name"
 }
 
+// Fuzzy returns ip, also matching names with a typo or two.
+func (ip *NamePredicate) Fuzzy() Predicate {
+	return &NamePredicate{name: ip.name, fuzzy: true}
+}
+
 func NewNamePredicate(name string) Predicate {
 	return &NamePredicate{
 		name: strings.TrimSpace(strings.ToLower(name)),
@@ -59,10 +91,13 @@ func NewNamePredicate(name string) Predicate {
 
 type RatingPredicate struct {
 	rating int
+	// max is the highest rating to match, if bounded is set.
+	max     int
+	bounded bool
 }
 
 func (rp *RatingPredicate) Match(candidate *Recipe) (string, bool) {
-	if candidate.Rating >= rp.rating {
+	if candidate.Rating >= rp.rating && (!rp.bounded || candidate.Rating <= rp.max) {
 		return candidate.FancyRating(), true
 	}
 	return "", false
@@ -77,3 +112,154 @@ func NewRatingPredicate(rating int) Predicate {
 		rating: rating,
 	}
 }
+
+// NewRatingRangePredicate matches recipes rated from min to max.
+func NewRatingRangePredicate(min, max int) Predicate {
+	return &RatingPredicate{
+		rating:  min,
+		max:     max,
+		bounded: true,
+	}
+}
+
+// CompoundPredicate is a Predicate made of others, which reports the matches
+// of each of them.
+type CompoundPredicate interface {
+	Predicate
+	MatchAll(*Recipe) ([]MatchResult, bool)
+}
+
+// matchAll matches p against candidate, expanding compound predicates into
+// the matches of their parts.
+func matchAll(p Predicate, candidate *Recipe) ([]MatchResult, bool) {
+	if cp, ok := p.(CompoundPredicate); ok {
+		return cp.MatchAll(candidate)
+	}
+	if sp, ok := p.(SnippetPredicate); ok {
+		snippet, score, ok := sp.Snippet(candidate)
+		if !ok {
+			return nil, false
+		}
+		return []MatchResult{{Predicate: p, Match: snippet.Text, Score: score, Highlights: snippet.Highlights}}, true
+	}
+	if sp, ok := p.(ScoredPredicate); ok {
+		match, score, ok := sp.Score(candidate)
+		if !ok {
+			return nil, false
+		}
+		return []MatchResult{{Predicate: p, Match: match, Score: score}}, true
+	}
+	match, ok := p.Match(candidate)
+	if !ok {
+		return nil, false
+	}
+	return []MatchResult{{Predicate: p, Match: match, Score: 1}}, true
+}
+
+// describeMatches formats results like "Gin (Ingredient), Lime (Ingredient)".
+func describeMatches(results []MatchResult) string {
+	var matches []string
+	for _, m := range results {
+		matches = append(matches, fmt.Sprintf("%s (%s)", m.Match, m.Predicate.Name()))
+	}
+	return strings.Join(matches, ", ")
+}
+
+// AndPredicate
+
+type AndPredicate struct {
+	predicates []Predicate
+}
+
+func (ap *AndPredicate) Match(candidate *Recipe) (string, bool) {
+	results, ok := ap.MatchAll(candidate)
+	return describeMatches(results), ok
+}
+
+func (ap *AndPredicate) MatchAll(candidate *Recipe) ([]MatchResult, bool) {
+	var results []MatchResult
+	for _, p := range ap.predicates {
+		r, ok := matchAll(p, candidate)
+		if !ok {
+			return nil, false
+		}
+		results = append(results, r...)
+	}
+	return results, true
+}
+
+func (ap *AndPredicate) Name() string {
+	return "And"
+}
+
+// NewAndPredicate matches recipes matching every one of predicates.
+func NewAndPredicate(predicates ...Predicate) Predicate {
+	return &AndPredicate{
+		predicates: predicates,
+	}
+}
+
+// OrPredicate
+
+type OrPredicate struct {
+	predicates []Predicate
+}
+
+func (op *OrPredicate) Match(candidate *Recipe) (string, bool) {
+	results, ok := op.MatchAll(candidate)
+	return describeMatches(results), ok
+}
+
+func (op *OrPredicate) MatchAll(candidate *Recipe) ([]MatchResult, bool) {
+	var results []MatchResult
+	matched := false
+	for _, p := range op.predicates {
+		if r, ok := matchAll(p, candidate); ok {
+			results = append(results, r...)
+			matched = true
+		}
+	}
+	return results, matched
+}
+
+func (op *OrPredicate) Name() string {
+	return "Or"
+}
+
+// NewOrPredicate matches recipes matching any of predicates.
+func NewOrPredicate(predicates ...Predicate) Predicate {
+	return &OrPredicate{
+		predicates: predicates,
+	}
+}
+
+// NotPredicate
+
+type NotPredicate struct {
+	predicate Predicate
+}
+
+func (np *NotPredicate) Match(candidate *Recipe) (string, bool) {
+	_, ok := np.MatchAll(candidate)
+	return "", ok
+}
+
+// MatchAll matches recipes that predicate doesn't. There's nothing to report
+// about what matched.
+func (np *NotPredicate) MatchAll(candidate *Recipe) ([]MatchResult, bool) {
+	if _, ok := matchAll(np.predicate, candidate); ok {
+		return nil, false
+	}
+	return nil, true
+}
+
+func (np *NotPredicate) Name() string {
+	return "Not"
+}
+
+// NewNotPredicate matches recipes that predicate doesn't match.
+func NewNotPredicate(predicate Predicate) Predicate {
+	return &NotPredicate{
+		predicate: predicate,
+	}
+}
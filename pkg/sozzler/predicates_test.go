@@ -127,3 +127,125 @@ func TestRatingPredicate(t *testing.T) {
 		})
 	}
 }
+
+func TestPredicateScore(t *testing.T) {
+	recipe := &sozzler.Recipe{
+		Name: "Gin Gimlet",
+		Components: []sozzler.Component{
+			{Ingredient: "Barr Hill Gin"},
+			{Ingredient: "Lime Juice"},
+			{Ingredient: "Simple Syrup"},
+		},
+	}
+
+	testCases := []struct {
+		predicate sozzler.Predicate
+		wantMatch string
+		wantScore float64
+	}{
+		{predicate: sozzler.NewIngredientPredicate("lime juice"), wantMatch: "Lime Juice", wantScore: sozzler.ExactScore},
+		{predicate: sozzler.NewIngredientPredicate("lime"), wantMatch: "Lime Juice", wantScore: sozzler.PrefixScore},
+		{predicate: sozzler.NewIngredientPredicate("gin"), wantMatch: "Barr Hill Gin", wantScore: sozzler.SubstringScore},
+		{predicate: sozzler.NewIngredientPredicate("simpel")},
+		{predicate: fuzzy(sozzler.NewIngredientPredicate("simpel")), wantMatch: "Simple Syrup", wantScore: sozzler.FuzzyScore},
+		{predicate: fuzzy(sozzler.NewIngredientPredicate("lmie juice")), wantMatch: "Lime Juice", wantScore: sozzler.FuzzyScore},
+		{predicate: fuzzy(sozzler.NewIngredientPredicate("lime")), wantMatch: "Lime Juice", wantScore: sozzler.PrefixScore},
+		{predicate: sozzler.NewIngredientPredicate("rum")},
+		{predicate: fuzzy(sozzler.NewIngredientPredicate("gni"))},
+		{predicate: sozzler.NewNamePredicate("gin gimlet"), wantMatch: "Gin Gimlet", wantScore: sozzler.ExactScore * sozzler.NameWeight},
+		{predicate: sozzler.NewNamePredicate("gimlet"), wantMatch: "Gin Gimlet", wantScore: sozzler.SubstringScore * sozzler.NameWeight},
+		{predicate: sozzler.NewNamePredicate("gimlett")},
+		{predicate: fuzzy(sozzler.NewNamePredicate("gimlett")), wantMatch: "Gin Gimlet", wantScore: sozzler.FuzzyScore * sozzler.NameWeight},
+	}
+	for i, tC := range testCases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			match, score, ok := tC.predicate.(sozzler.ScoredPredicate).Score(recipe)
+			assert.Equal(t, tC.wantMatch, match)
+			assert.Equal(t, tC.wantScore, score)
+			assert.Equal(t, tC.wantScore > 0, ok)
+
+			match, ok = tC.predicate.Match(recipe)
+			assert.Equal(t, tC.wantMatch, match)
+			assert.Equal(t, tC.wantScore > 0, ok)
+		})
+	}
+}
+
+func fuzzy(p sozzler.Predicate) sozzler.Predicate {
+	return p.(sozzler.FuzzyPredicate).Fuzzy()
+}
+
+func TestSearchRanking(t *testing.T) {
+	catalog := sozzler.RecipeCatalog{Recipes: []*sozzler.Recipe{
+		{Name: "Gin and Tonic", Components: []sozzler.Component{{Ingredient: "Gin"}, {Ingredient: "Tonic"}}},
+		{Name: "Daiquiri", Components: []sozzler.Component{{Ingredient: "Rum"}, {Ingredient: "Lime Juice"}}},
+		{Name: "Gimlet", Components: []sozzler.Component{{Ingredient: "Gin"}, {Ingredient: "Lime Juice"}}},
+		{Name: "Lime Rickey", Components: []sozzler.Component{{Ingredient: "Lime Juice"}, {Ingredient: "Soda"}}},
+	}}
+
+	results := catalog.Search([]sozzler.Predicate{
+		sozzler.NewIngredientPredicate("gin"),
+		sozzler.NewIngredientPredicate("lime"),
+	})
+
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Recipe.Name)
+	}
+	assert.Equal(t, []string{"Gimlet", "Gin and Tonic", "Daiquiri", "Lime Rickey"}, names)
+	assert.Equal(t, sozzler.ExactScore+sozzler.PrefixScore, results[0].Score)
+
+	results = catalog.Search([]sozzler.Predicate{
+		sozzler.NewIngredientPredicate("lime"),
+		sozzler.NewNamePredicate("lime"),
+	})
+	assert.Equal(t, "Lime Rickey", results[0].Recipe.Name, "name matches count more")
+}
+
+func TestSearchFuzzyFallback(t *testing.T) {
+	catalog := sozzler.RecipeCatalog{Recipes: []*sozzler.Recipe{
+		{Name: "Gimlet", Components: []sozzler.Component{{Ingredient: "Gin"}, {Ingredient: "Lime Juice"}, {Ingredient: "Simple Syrup"}}},
+		{Name: "Daiquiri", Components: []sozzler.Component{{Ingredient: "Rum"}, {Ingredient: "Lime Juice"}, {Ingredient: "Simple Syrup"}}},
+		{Name: "Negroni", Components: []sozzler.Component{{Ingredient: "Gin"}, {Ingredient: "Campari"}}},
+	}}
+
+	testCases := []struct {
+		desc       string
+		predicates []sozzler.Predicate
+		want       []string
+	}{
+		{
+			desc:       "as typed",
+			predicates: []sozzler.Predicate{sozzler.NewIngredientPredicate("campar")},
+			want:       []string{"Negroni"},
+		},
+		{
+			desc:       "fuzzy",
+			predicates: []sozzler.Predicate{sozzler.NewIngredientPredicate("campri")},
+			want:       []string{"Negroni"},
+		},
+		{
+			desc:       "only when nothing matches",
+			predicates: []sozzler.Predicate{sozzler.NewIngredientPredicate("rum"), sozzler.NewIngredientPredicate("campri")},
+			want:       []string{"Daiquiri"},
+		},
+		{
+			desc:       "name",
+			predicates: []sozzler.Predicate{sozzler.NewNamePredicate("negorni")},
+			want:       []string{"Negroni"},
+		},
+		{
+			desc:       "not in queries",
+			predicates: []sozzler.Predicate{must(sozzler.ParseQuery("i:campri or i:vodka"))},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var names []string
+			for _, r := range catalog.Search(tC.predicates) {
+				names = append(names, r.Recipe.Name)
+			}
+			assert.Equal(t, tC.want, names)
+		})
+	}
+}
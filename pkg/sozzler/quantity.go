@@ -2,107 +2,483 @@ package sozzler
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Quantity is an exact amount, like 3/4 or 2, or a range of amounts, like the
+// 1-2 of "1-2 dashes". Amounts are kept as reduced fractions so that equal
+// Quantities are ==. The zero Quantity has no amount, as for a garnish.
 type Quantity struct {
-	s string
-	f float64
+	num, den int64 // den is 0 if there's no amount
+	// maxNum and maxDen are the top of a range. maxDen is 0 if q isn't a range.
+	maxNum, maxDen int64
+	// decimal is set for amounts that read better as decimals, like 7.5 ml.
+	decimal bool
 }
 
+// NewQuantity returns the quantity num/den.
+func NewQuantity(num, den int64) Quantity {
+	// reduced, num/den still fits
+	q, _ := quantity(big.NewRat(num, den))
+	return q
+}
+
+// NewRange returns the range of quantities from min to max. It returns min if
+// they're the same.
+func NewRange(min, max Quantity) Quantity {
+	min, max = min.Min(), max.Max()
+	if min.Cmp(max) == 0 {
+		return min
+	}
+	if min.Cmp(max) > 0 {
+		min, max = max, min
+	}
+	min.maxNum, min.maxDen = max.num, max.den
+	min.decimal = min.decimal || max.decimal
+	return min
+}
+
+// quantity returns r as a Quantity, or the zero Quantity if r is nil. It fails
+// if r's numerator or denominator doesn't fit in an int64.
+func quantity(r *big.Rat) (Quantity, error) {
+	if r == nil {
+		return Quantity{}, nil
+	}
+	if !r.Num().IsInt64() || !r.Denom().IsInt64() {
+		return Quantity{}, fmt.Errorf("%s is too large or too precise", r.RatString())
+	}
+	return Quantity{num: r.Num().Int64(), den: r.Denom().Int64()}, nil
+}
+
+// IsRange reports whether q is a range of amounts, like 1-2.
+func (q Quantity) IsRange() bool {
+	return q.maxDen != 0
+}
+
+// Min returns the bottom of a range, or q if it isn't one.
+func (q Quantity) Min() Quantity {
+	return Quantity{num: q.num, den: q.den, decimal: q.decimal}
+}
+
+// Max returns the top of a range, or q if it isn't one.
+func (q Quantity) Max() Quantity {
+	if !q.IsRange() {
+		return q
+	}
+	return Quantity{num: q.maxNum, den: q.maxDen, decimal: q.decimal}
+}
+
+// each returns q with fn applied to both ends of a range, or to q if it isn't
+// one.
+func (q Quantity) each(fn func(Quantity) Quantity) Quantity {
+	if !q.IsRange() {
+		return fn(q)
+	}
+	return NewRange(fn(q.Min()), fn(q.Max()))
+}
+
+// String formats q as a reduced mixed fraction, like "1 1/2", or a range of
+// them, like "1-2". It returns "" if q has no amount.
 func (q Quantity) String() string {
-	parts := strings.Split(q.s, "/1")
-	if parts[0] == "0" {
+	if q.IsZero() {
 		return ""
 	}
-	return parts[0]
+	if q.IsRange() {
+		return q.Min().format() + "-" + q.Max().format()
+	}
+	return q.format()
 }
 
+// format formats one end of q.
+func (q Quantity) format() string {
+	if q.decimal {
+		return q.decimalString()
+	}
+	if q.den == 0 {
+		return "0"
+	}
+
+	num, sign := q.num, ""
+	if num < 0 {
+		num, sign = -num, "-"
+	}
+	whole, rem := num/q.den, num%q.den
+
+	switch {
+	case rem == 0:
+		return fmt.Sprintf("%s%d", sign, whole)
+	case whole == 0:
+		return fmt.Sprintf("%s%d/%d", sign, rem, q.den)
+	}
+	return fmt.Sprintf("%s%d %d/%d", sign, whole, rem, q.den)
+}
+
+// decimalString formats one end of q as a decimal: exactly, if it has one, or
+// else to 3 places.
+func (q Quantity) decimalString() string {
+	if s, ok := exactDecimal(q.rat()); ok {
+		return s
+	}
+	s := q.rat().FloatString(3)
+	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+}
+
+// exactDecimal formats r as a decimal with every place it needs, like
+// "0.0625", or reports false if it repeats forever, like 1/3.
+func exactDecimal(r *big.Rat) (string, bool) {
+	// r ends after as many places as the most 2s or 5s in its denominator
+	den := new(big.Int).Set(r.Denom())
+	places := 0
+	for _, p := range []int64{2, 5} {
+		n, mod, prime := 0, new(big.Int), big.NewInt(p)
+		for {
+			quo, m := new(big.Int).QuoRem(den, prime, mod)
+			if m.Sign() != 0 {
+				break
+			}
+			den, n = quo, n+1
+		}
+		places = max(places, n)
+	}
+	if den.Cmp(big.NewInt(1)) != 0 {
+		return "", false
+	}
+	return r.FloatString(places), true
+}
+
+// Float returns q as a float, or the middle of a range.
 func (q Quantity) Float() float64 {
-	return q.f
+	f, _ := q.Rat().Float64()
+	return f
 }
 
-func (q Quantity) Scale(factor int) Quantity {
-	q.f *= float64(factor)
-	q.s = stringer(q.f)
-	return q
+// Rat returns q as a rational number, or the middle of a range.
+func (q Quantity) Rat() *big.Rat {
+	if !q.IsRange() {
+		return q.rat()
+	}
+	mid := new(big.Rat).Add(q.Min().rat(), q.Max().rat())
+	return mid.Quo(mid, big.NewRat(2, 1))
 }
 
-func stringer(f float64) string {
-	if float64(f) == 0 {
-		return ""
+// rat returns the bottom end of q as a rational number.
+func (q Quantity) rat() *big.Rat {
+	if q.den == 0 {
+		return new(big.Rat)
+	}
+	return big.NewRat(q.num, q.den)
+}
+
+// Add returns q + o. Adding ranges adds their bottoms and their tops. It fails
+// if the sum is too large or too precise to be a Quantity.
+func (q Quantity) Add(o Quantity) (Quantity, error) {
+	add := func(a, b Quantity) (Quantity, error) {
+		sum, err := quantity(new(big.Rat).Add(a.rat(), b.rat()))
+		sum.decimal = a.decimal
+		return sum, err
+	}
+	if !q.IsRange() && !o.IsRange() {
+		return add(q, o)
+	}
+	min, err := add(q.Min(), o.Min())
+	if err != nil {
+		return Quantity{}, err
+	}
+	max, err := add(q.Max(), o.Max())
+	if err != nil {
+		return Quantity{}, err
+	}
+	return NewRange(min, max), nil
+}
+
+// Mul returns q × o. o should not be a range. It fails if the product is too
+// large or too precise to be a Quantity.
+func (q Quantity) Mul(o Quantity) (Quantity, error) {
+	mul := func(end Quantity) (Quantity, error) {
+		product, err := quantity(new(big.Rat).Mul(end.rat(), o.rat()))
+		product.decimal = end.decimal
+		return product, err
+	}
+	if !q.IsRange() {
+		return mul(q)
 	}
+	min, err := mul(q.Min())
+	if err != nil {
+		return Quantity{}, err
+	}
+	max, err := mul(q.Max())
+	if err != nil {
+		return Quantity{}, err
+	}
+	return NewRange(min, max), nil
+}
 
-	fractionMap := map[float64]string{
-		0.5:   "1/2", // "½"
-		0.25:  "1/4", // "¼"
-		0.75:  "3/4", // "¾"
-		0.125: "1/8",
+// Cmp compares q and o, returning -1, 0, or +1. Ranges compare by their
+// bottoms, then their tops. A Quantity with no amount is the same as 0.
+func (q Quantity) Cmp(o Quantity) int {
+	if c := q.rat().Cmp(o.rat()); c != 0 {
+		return c
 	}
+	return q.Max().rat().Cmp(o.Max().rat())
+}
 
-	intPart := int(f)
-	fracPart := f - float64(intPart)
+// Scale multiplies q by factor. Quantities with no amount, like garnishes, are
+// left alone. It fails if factor or the result is too large or too precise to
+// be a Quantity.
+func (q Quantity) Scale(factor *big.Rat) (Quantity, error) {
+	if q.IsZero() {
+		return q, nil
+	}
+	f, err := quantity(factor)
+	if err != nil {
+		return Quantity{}, fmt.Errorf("invalid scale: %w", err)
+	}
+	return q.Mul(f)
+}
 
-	if fancy, ok := fractionMap[fracPart]; ok {
-		if intPart == 0 {
-			return fancy
+// Round returns q rounded to the nearest multiple of step, but never less than
+// step, so small amounts don't round away to nothing. An amount too large to
+// round is left as it is.
+func (q Quantity) Round(step *big.Rat) Quantity {
+	if q.IsZero() {
+		return q
+	}
+	return q.each(func(end Quantity) Quantity {
+		n := new(big.Rat).Quo(end.rat(), step)
+		// round half up: floor(n + 1/2)
+		n.Add(n, big.NewRat(1, 2))
+		whole := new(big.Int).Quo(n.Num(), n.Denom())
+		if whole.Sign() <= 0 {
+			whole.SetInt64(1)
 		}
-		return fmt.Sprintf("%d %s", intPart, fancy)
+		r := new(big.Rat).SetInt(whole)
+		rounded, err := quantity(r.Mul(r, step))
+		if err != nil {
+			return end
+		}
+		rounded.decimal = end.decimal
+		return rounded
+	})
+}
+
+// measurable are the fractions of a unit a bartender can measure.
+var measurable = []*big.Rat{
+	big.NewRat(0, 1),
+	big.NewRat(1, 8),
+	big.NewRat(1, 4),
+	big.NewRat(1, 3),
+	big.NewRat(3, 8),
+	big.NewRat(1, 2),
+	big.NewRat(5, 8),
+	big.NewRat(2, 3),
+	big.NewRat(3, 4),
+	big.NewRat(7, 8),
+	big.NewRat(1, 1),
+}
+
+// Measurable returns q rounded to the nearest amount a bartender can measure:
+// a whole number plus eighths or thirds. Amounts too small to round to
+// anything become 1/8, and amounts too large to round are left as they are.
+func (q Quantity) Measurable() Quantity {
+	if q.IsZero() {
+		return q
 	}
+	return q.each(func(end Quantity) Quantity {
+		whole := new(big.Rat).SetInt64(end.num / end.den)
+		frac := new(big.Rat).Sub(end.rat(), whole)
+
+		nearest, distance := measurable[0], new(big.Rat).Abs(frac)
+		for _, m := range measurable[1:] {
+			d := new(big.Rat).Sub(frac, m)
+			if d.Abs(d).Cmp(distance) < 0 {
+				nearest, distance = m, d
+			}
+		}
+
+		r := new(big.Rat).Add(whole, nearest)
+		if r.Sign() == 0 {
+			r.Set(measurable[1])
+		}
+		rounded, err := quantity(r)
+		if err != nil {
+			return end
+		}
+		rounded.decimal = end.decimal
+		return rounded
+	})
+}
+
+// ParseFactor parses a scaling factor written as a whole number, fraction, or
+// decimal, like "2", "1/2", or "1.5".
+func ParseFactor(s string) (*big.Rat, error) {
+	factor, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || factor.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid scale %q: use a positive number like 2, 1/2, or 1.5", s)
+	}
+	return factor, nil
+}
+
+// IsZero reports whether q has no amount, as for a garnish. Zero quantities are
+// left out of recipe files.
+func (q Quantity) IsZero() bool {
+	return q.num == 0 && q.maxNum == 0
+}
 
-	return fmt.Sprint(f)
+// IsZeroAmount reports whether q was written as an amount of zero, like "0",
+// rather than having no amount at all. A range from zero, like 0-1, isn't.
+func (q Quantity) IsZeroAmount() bool {
+	return q.den != 0 && q.num == 0 && !q.IsRange()
 }
 
+// MarshalYAML writes q as a reduced fraction, like "2" or "3/2", or an exact
+// decimal if it was written as one, so that older versions of sozzler can read it.
+// Ranges are written like "1-2".
 func (q Quantity) MarshalYAML() (interface{}, error) {
-	if q.s == "" {
-		panic("wtf")
+	return q.text(), nil
+}
+
+// text is q as it's written in recipe files.
+func (q Quantity) text() string {
+	end := func(e Quantity) string {
+		if e.decimal {
+			if s, ok := exactDecimal(e.rat()); ok {
+				return s
+			}
+		}
+		return e.rat().RatString()
+	}
+	if q.IsRange() {
+		return end(q.Min()) + "-" + end(q.Max())
 	}
-	return q.s, nil
+	return end(q)
 }
 
 func (q *Quantity) UnmarshalYAML(value *yaml.Node) error {
+	// errors are yaml.TypeErrors so that the decoder reports them by line, and
+	// carries on to find any others in the same recipe.
 	var s string
 	if err := value.Decode(&s); err != nil {
-		return fmt.Errorf("quantity: unsupported YAML type %q", value.Tag)
+		return &yaml.TypeError{Errors: []string{
+			fmt.Sprintf("line %d: quantity: unsupported YAML type %q", value.Line, value.Tag),
+		}}
 	}
 
-	v, err := parseFraction(s)
+	parsed, err := parseQuantity(s)
 	if err != nil {
-		return fmt.Errorf("quantity: %w", err)
+		return &yaml.TypeError{Errors: []string{
+			fmt.Sprintf("line %d: quantity: %v", value.Line, err),
+		}}
 	}
-	*q = Quantity{s: s, f: v}
+	*q = parsed
 	return nil
 }
 
+// ParseQuantity parses a quantity written as a whole number, fraction, mixed
+// number, or decimal, like "2", "3/4", "1 1/2", "1½", or "1.5", or a range of
+// them, like "1-2".
 func ParseQuantity(s string) (*Quantity, error) {
-	f, err := parseFraction(s)
+	q, err := parseQuantity(s)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't parse quantity %q: %w", s, err)
 	}
-	return &Quantity{s: s, f: f}, nil
+	return &q, nil
+}
+
+func parseQuantity(s string) (Quantity, error) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "–", "-"))
+	if s == "" {
+		return Quantity{}, nil
+	}
+
+	lo, hi, ranged := strings.Cut(s, "-")
+	min, err := parseAmount(lo)
+	if err != nil || !ranged {
+		return min, err
+	}
+	max, err := parseAmount(hi)
+	if err != nil {
+		return Quantity{}, err
+	}
+	if min.Cmp(max) > 0 {
+		return Quantity{}, fmt.Errorf("invalid range %q: %s is more than %s", s, min, max)
+	}
+	return NewRange(min, max), nil
 }
 
-func parseFraction(s string) (float64, error) {
-	s = strings.TrimSpace(s)
+// vulgarFractions are the unicode fractions that appear in recipes.
+var vulgarFractions = map[rune]*big.Rat{
+	'¼': big.NewRat(1, 4),
+	'½': big.NewRat(1, 2),
+	'¾': big.NewRat(3, 4),
+	'⅓': big.NewRat(1, 3),
+	'⅔': big.NewRat(2, 3),
+	'⅕': big.NewRat(1, 5),
+	'⅖': big.NewRat(2, 5),
+	'⅗': big.NewRat(3, 5),
+	'⅘': big.NewRat(4, 5),
+	'⅙': big.NewRat(1, 6),
+	'⅚': big.NewRat(5, 6),
+	'⅛': big.NewRat(1, 8),
+	'⅜': big.NewRat(3, 8),
+	'⅝': big.NewRat(5, 8),
+	'⅞': big.NewRat(7, 8),
+}
+
+// parseAmount parses one end of a quantity: a whole number, decimal, fraction
+// like "3/4", or mixed number like "1 1/2" or "1½".
+func parseAmount(s string) (Quantity, error) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "⁄", "/"))
 	if s == "" {
-		return 0, nil
+		return Quantity{}, fmt.Errorf("missing amount")
+	}
+
+	whole, frac := "", s
+	if fields := strings.Fields(s); len(fields) == 2 {
+		whole, frac = fields[0], fields[1]
+	} else if r, size := utf8.DecodeLastRuneInString(s); vulgarFractions[r] != nil {
+		whole, frac = s[:len(s)-size], s[len(s)-size:]
+	}
+
+	r, decimal, err := parseFraction(frac)
+	if err != nil {
+		return Quantity{}, err
 	}
+	if whole != "" {
+		n, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || n < 0 || decimal || r.Cmp(big.NewRat(1, 1)) >= 0 {
+			return Quantity{}, fmt.Errorf("invalid mixed number %q", s)
+		}
+		r.Add(r, new(big.Rat).SetInt64(n))
+	}
+
+	q, err := quantity(r)
+	if err != nil {
+		return Quantity{}, err
+	}
+	q.decimal = decimal
+	return q, nil
+}
 
-	if !strings.Contains(s, "/") {
-		s += "/1"
+// parseFraction parses a whole number, decimal, unicode fraction, or fraction
+// like "3/4", reporting whether it was written as a decimal.
+func parseFraction(s string) (*big.Rat, bool, error) {
+	if r, _ := utf8.DecodeRuneInString(s); vulgarFractions[r] != nil && utf8.RuneCountInString(s) == 1 {
+		return new(big.Rat).Set(vulgarFractions[r]), false, nil
 	}
 
-	parts := strings.SplitN(s, "/", 2)
-	if len(parts) != 2 {
-		return 0, fmt.Errorf("invalid fraction %q", s)
+	num, den, slashed := strings.Cut(s, "/")
+	if !slashed {
+		den = "1"
 	}
-	num, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
-	den, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
-	if err1 != nil || err2 != nil || den == 0 {
-		return 0, fmt.Errorf("invalid fraction %q", s)
+	n, ok1 := new(big.Rat).SetString(strings.TrimSpace(num))
+	d, ok2 := new(big.Rat).SetString(strings.TrimSpace(den))
+	if !ok1 || !ok2 || d.Sign() == 0 || strings.Contains(den, "/") {
+		return nil, false, fmt.Errorf("invalid fraction %q", s)
 	}
-	return num / den, nil
+	return n.Quo(n, d), !slashed && strings.Contains(num, "."), nil
 }
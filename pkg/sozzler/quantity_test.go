@@ -0,0 +1,200 @@
+package sozzler_test
+
+import (
+	"math/big"
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestScale(t *testing.T) {
+	testCases := []struct {
+		quantity string
+		factor   string
+		want     string
+	}{
+		{quantity: "2", factor: "1", want: "2"},
+		{quantity: "2/1", factor: "2", want: "4"},
+		{quantity: "3/4", factor: "1/2", want: "3/8"},
+		{quantity: "3/4", factor: "1.5", want: "1 1/8"},
+		{quantity: "1/2", factor: "3", want: "1 1/2"},
+		{quantity: "1/3", factor: "2", want: "2/3"},
+		{quantity: "2", factor: "1/3", want: "2/3"},
+		{quantity: "1/3", factor: "3", want: "1"},
+		{quantity: "1", factor: "0.3", want: "3/10"},
+		{quantity: "1/8", factor: "1/2", want: "1/16"},
+		{quantity: "15/16", factor: "1", want: "15/16"},
+		{quantity: "3/4", factor: "6/4", want: "1 1/8"},
+		{quantity: "", factor: "2", want: ""},
+		{quantity: "0/1", factor: "2", want: ""},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.quantity+" x "+tC.factor, func(t *testing.T) {
+			factor, err := sozzler.ParseFactor(tC.factor)
+			require.NoError(t, err)
+
+			got, err := must(sozzler.ParseQuantity(tC.quantity)).Scale(factor)
+			require.NoError(t, err)
+			assert.Equal(t, tC.want, got.String())
+		})
+	}
+}
+
+func TestQuantityString(t *testing.T) {
+	testCases := []struct {
+		given string
+		want  string
+	}{
+		{given: "2/1", want: "2"},
+		{given: "6/8", want: "3/4"},
+		{given: "3/2", want: "1 1/2"},
+		{given: "10/3", want: "3 1/3"},
+		{given: "1/10", want: "1/10"},
+		{given: "0.75", want: "0.75"},
+		{given: "1 1/2", want: "1 1/2"},
+		{given: "1½", want: "1 1/2"},
+		{given: "⅓", want: "1/3"},
+		{given: "1-2", want: "1-2"},
+		{given: "1/2 – 3/4", want: "1/2-3/4"},
+		{given: "2-2", want: "2"},
+		{given: "0/1", want: ""},
+		{given: "", want: ""},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.given, func(t *testing.T) {
+			assert.Equal(t, tC.want, must(sozzler.ParseQuantity(tC.given)).String())
+		})
+	}
+}
+
+func TestQuantityArithmetic(t *testing.T) {
+	third := sozzler.NewQuantity(1, 3)
+
+	assert.Equal(t, sozzler.NewQuantity(1, 1), must(must(third.Add(third)).Add(third)))
+	assert.Equal(t, sozzler.NewQuantity(1, 9), must(third.Mul(third)))
+	assert.Equal(t, 1, third.Cmp(sozzler.NewQuantity(1, 4)))
+	assert.Equal(t, -1, sozzler.Quantity{}.Cmp(third))
+	assert.Equal(t, 0, third.Cmp(*must(sozzler.ParseQuantity("2/6"))))
+
+	sixth := must(must(sozzler.ParseQuantity("0.5")).Scale(big.NewRat(1, 3)))
+	assert.Equal(t, "0.167", sixth.String(), "decimals that never end are shown to 3 places")
+	assert.Equal(t, "1/6\n", string(must(yaml.Marshal(sixth))), "and written exactly")
+}
+
+func TestMeasurable(t *testing.T) {
+	testCases := []struct {
+		given string
+		want  string
+	}{
+		{given: "3/4", want: "3/4"},
+		{given: "3/10", want: "1/3"},
+		{given: "1/16", want: "1/8"},
+		{given: "1/100", want: "1/8"},
+		{given: "19/10", want: "1 7/8"},
+		{given: "199/100", want: "2"},
+		{given: "", want: ""},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.given, func(t *testing.T) {
+			assert.Equal(t, tC.want, must(sozzler.ParseQuantity(tC.given)).Measurable().String())
+		})
+	}
+}
+
+func TestParseFactor(t *testing.T) {
+	for _, given := range []string{"0", "-1", "half", "1/0", ""} {
+		_, err := sozzler.ParseFactor(given)
+		assert.Error(t, err, given)
+	}
+
+	factor, err := sozzler.ParseFactor(" 1.5 ")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewRat(3, 2), factor)
+}
+
+func TestRecipeScale(t *testing.T) {
+	recipe := &sozzler.Recipe{
+		Name: "Daiquiri",
+		Components: []sozzler.Component{
+			*component("rum", "2", "oz"),
+			*component("lime wheel", "", ""),
+		},
+	}
+
+	scaled, err := recipe.Scale(big.NewRat(1, 2))
+	require.NoError(t, err)
+	assert.Equal(t, "1", scaled.Components[0].Quantity.String())
+	assert.Equal(t, "", scaled.Components[1].Quantity.String())
+
+	scaled, err = recipe.Scale(big.NewRat(1, 5))
+	require.NoError(t, err)
+	assert.Equal(t, "3/8", scaled.Components[0].Quantity.String(), "rounded to a measurable amount")
+	assert.Equal(t, "", scaled.Components[1].Quantity.String())
+	assert.Equal(t, "2", recipe.Components[0].Quantity.String(), "recipe is not changed")
+
+	_, err = recipe.Scale(big.NewRat(1<<62, 3))
+	assert.EqualError(t, err, `couldn't scale "rum" in "Daiquiri": 9223372036854775808/3 is too large or too precise`)
+}
+
+func TestParseQuantityErrors(t *testing.T) {
+	for _, given := range []string{"1/0", "1//2", "1/2/3", "2-1", "1-", "-1", "1 3/2", "1.5 1/2", "1 2 3", "x", "12345678901234567890", "0.12345678901234567890", "1/12345678901234567890"} {
+		_, err := sozzler.ParseQuantity(given)
+		assert.Error(t, err, given)
+	}
+}
+
+func TestRange(t *testing.T) {
+	q := *must(sozzler.ParseQuantity("1-2"))
+	assert.True(t, q.IsRange())
+	assert.Equal(t, "1", q.Min().String())
+	assert.Equal(t, "2", q.Max().String())
+	assert.Equal(t, 1.5, q.Float())
+
+	assert.Equal(t, "1/2-1", must(q.Scale(big.NewRat(1, 2))).String())
+	assert.Equal(t, "2-3", must(q.Add(sozzler.NewQuantity(1, 1))).String())
+	assert.Equal(t, "1/4-3/8", must(q.Scale(big.NewRat(1, 5))).Measurable().String())
+
+	ml, err := sozzler.Convert(*must(sozzler.ParseQuantity("1-1 1/2")), "oz", "ml")
+	require.NoError(t, err)
+	assert.Equal(t, "30-45", ml.Round(big.NewRat(5, 1)).String())
+
+	assert.False(t, must(sozzler.ParseQuantity("0-1")).IsZeroAmount())
+	assert.True(t, must(sozzler.ParseQuantity("0")).IsZeroAmount())
+	assert.False(t, must(sozzler.ParseQuantity("")).IsZeroAmount())
+
+	single := sozzler.NewRange(sozzler.NewQuantity(1, 4), sozzler.NewQuantity(1, 4))
+	assert.False(t, single.IsRange())
+}
+
+func TestQuantityYAML(t *testing.T) {
+	testCases := []struct {
+		given string
+		want  string
+	}{
+		{given: "'2/1'", want: "\"2\"\n"},
+		{given: "'3/4'", want: "3/4\n"},
+		{given: "1 1/2", want: "3/2\n"},
+		{given: "1½", want: "3/2\n"},
+		{given: "1.5", want: "\"1.5\"\n"},
+		{given: "0.0625", want: "\"0.0625\"\n"},
+		{given: "1.33333", want: "\"1.33333\"\n"},
+		{given: "7.50", want: "\"7.5\"\n"},
+		{given: "0.0625-0.5", want: "0.0625-0.5\n"},
+		{given: "2", want: "\"2\"\n"},
+		{given: "1-2", want: "1-2\n"},
+		{given: "1/2-3/4", want: "1/2-3/4\n"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.given, func(t *testing.T) {
+			var q sozzler.Quantity
+			require.NoError(t, yaml.Unmarshal([]byte(tC.given), &q))
+
+			got, err := yaml.Marshal(q)
+			require.NoError(t, err)
+			assert.Equal(t, tC.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package sozzler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// QueryError is a query that couldn't be parsed. Pos is the byte offset in
+// Query of the token at fault.
+type QueryError struct {
+	Query string
+	Pos   int
+	Msg   string
+}
+
+// Error describes the problem, and points at the token at fault:
+//
+//	invalid query: expected a value after "ingredient:"
+//	  ingredient: and rum
+//	              ^
+func (e *QueryError) Error() string {
+	col := utf8.RuneCountInString(e.Query[:e.Pos])
+	return fmt.Sprintf("invalid query: %s\n  %s\n  %s^", e.Msg, e.Query, strings.Repeat(" ", col))
+}
+
+type queryTokenKind int
+
+const (
+	queryEOF queryTokenKind = iota
+	queryWord
+	queryString
+	queryLParen
+	queryRParen
+	queryColon
+	queryOp
+)
+
+type queryToken struct {
+	kind queryTokenKind
+	text string
+	pos  int
+}
+
+// is reports whether t is the keyword word, like "and".
+func (t queryToken) is(word string) bool {
+	return t.kind == queryWord && strings.EqualFold(t.text, word)
+}
+
+// describe names t for error messages.
+func (t queryToken) describe() string {
+	if t.kind == queryEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.text)
+}
+
+// lexQuery splits query into tokens, ending with a queryEOF.
+func lexQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	for i := 0; i < len(query); {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: queryLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: queryRParen, text: ")", pos: i})
+			i++
+		case r == ':':
+			tokens = append(tokens, queryToken{kind: queryColon, text: ":", pos: i})
+			i++
+		case r == '<' || r == '>' || r == '=':
+			op := query[i : i+1]
+			if r != '=' && strings.HasPrefix(query[i+1:], "=") {
+				op += "="
+			}
+			tokens = append(tokens, queryToken{kind: queryOp, text: op, pos: i})
+			i += len(op)
+		case r == '"' || r == '\'':
+			end := strings.IndexRune(query[i+1:], r)
+			if end < 0 {
+				return nil, &QueryError{Query: query, Pos: i, Msg: "unterminated quote"}
+			}
+			tokens = append(tokens, queryToken{kind: queryString, text: query[i+1 : i+1+end], pos: i})
+			i += end + 2
+		default:
+			end := strings.IndexFunc(query[i:], func(r rune) bool {
+				return unicode.IsSpace(r) || strings.ContainsRune(`()":<>=`, r)
+			})
+			if end < 0 {
+				end = len(query) - i
+			}
+			tokens = append(tokens, queryToken{kind: queryWord, text: query[i : i+end], pos: i})
+			i += end
+		}
+	}
+	return append(tokens, queryToken{kind: queryEOF, pos: len(query)}), nil
+}
+
+// queryParser is a recursive descent parser for queries.
+type queryParser struct {
+	query  string
+	tokens []queryToken
+	next   int
+}
+
+func (qp *queryParser) peek() queryToken {
+	return qp.tokens[qp.next]
+}
+
+func (qp *queryParser) take() queryToken {
+	t := qp.tokens[qp.next]
+	if t.kind != queryEOF {
+		qp.next++
+	}
+	return t
+}
+
+func (qp *queryParser) errorAt(t queryToken, format string, args ...interface{}) error {
+	return &QueryError{Query: qp.query, Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// ParseQuery parses a query into a Predicate. A query is made of terms like
+//
+//	ingredient:gin     an ingredient contains gin; also i:gin
+//	name:"mai tai"     the name contains mai tai; also n:
+//	rating>=4          rated 4 or more; also >, <, <=, =, and rating:4 for >=
+//	lime               the name or an ingredient contains lime
+//
+// combined with and, or, not, and parentheses. Values with spaces are
+// quoted. Terms side by side are anded, and and binds tighter than or, so
+//
+//	ingredient:gin and ingredient:lime and not ingredient:egg and rating>=4
+//
+// finds well rated gin and lime drinks without egg.
+func ParseQuery(query string) (Predicate, error) {
+	tokens, err := lexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	qp := &queryParser{query: query, tokens: tokens}
+	if qp.peek().kind == queryEOF {
+		return nil, qp.errorAt(qp.peek(), "empty query")
+	}
+
+	p, err := qp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := qp.peek(); t.kind != queryEOF {
+		if t.kind == queryRParen {
+			return nil, qp.errorAt(t, "unmatched )")
+		}
+		return nil, qp.errorAt(t, "unexpected %s", t.describe())
+	}
+	return p, nil
+}
+
+// parseOr parses and-expressions separated by or.
+func (qp *queryParser) parseOr() (Predicate, error) {
+	p, err := qp.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	predicates := []Predicate{p}
+	for qp.peek().is("or") {
+		qp.take()
+		p, err := qp.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	if len(predicates) == 1 {
+		return p, nil
+	}
+	return NewOrPredicate(predicates...), nil
+}
+
+// parseAnd parses unary expressions separated by and, or side by side.
+func (qp *queryParser) parseAnd() (Predicate, error) {
+	p, err := qp.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	predicates := []Predicate{p}
+	for {
+		t := qp.peek()
+		if t.is("and") {
+			qp.take()
+		} else if t.kind == queryEOF || t.kind == queryRParen || t.is("or") {
+			break
+		}
+		p, err := qp.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	if len(predicates) == 1 {
+		return p, nil
+	}
+	return NewAndPredicate(predicates...), nil
+}
+
+// parseUnary parses a term, a parenthesized expression, or not either.
+func (qp *queryParser) parseUnary() (Predicate, error) {
+	t := qp.take()
+	switch {
+	case t.is("not"):
+		p, err := qp.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return NewNotPredicate(p), nil
+	case t.kind == queryLParen:
+		p, err := qp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if qp.peek().kind != queryRParen {
+			return nil, qp.errorAt(t, "unmatched (")
+		}
+		qp.take()
+		return p, nil
+	case t.is("and") || t.is("or"):
+		return nil, qp.errorAt(t, "expected a term before %s", t.describe())
+	case t.kind == queryString:
+		return newTextPredicate(t.text), nil
+	case t.kind == queryWord:
+		if next := qp.peek(); next.kind == queryColon || next.kind == queryOp {
+			return qp.parseField(t)
+		}
+		return newTextPredicate(t.text), nil
+	}
+	return nil, qp.errorAt(t, "expected a term, found %s", t.describe())
+}
+
+// parseField parses the operator and value of a term like rating>=4, after
+// the field.
+func (qp *queryParser) parseField(field queryToken) (Predicate, error) {
+	op := qp.take()
+	value := qp.peek()
+	if value.kind != queryWord && value.kind != queryString || value.is("and") || value.is("or") || value.is("not") {
+		// keywords have to be quoted to be values
+		return nil, qp.errorAt(value, "expected a value after %q", field.text+op.text)
+	}
+	qp.take()
+
+	switch strings.ToLower(field.text) {
+	case "ingredient", "ingredients", "i":
+		if op.kind != queryColon {
+			return nil, qp.errorAt(op, "use %s:", field.text)
+		}
+		return NewIngredientPredicate(value.text), nil
+	case "name", "names", "n":
+		if op.kind != queryColon {
+			return nil, qp.errorAt(op, "use %s:", field.text)
+		}
+		return NewNamePredicate(value.text), nil
+	case "rating", "r":
+		rating, err := strconv.Atoi(value.text)
+		if err != nil || rating < 0 {
+			return nil, qp.errorAt(value, "rating must be a whole number, not %s", value.describe())
+		}
+		switch op.text {
+		case ":", ">=":
+			return NewRatingPredicate(rating), nil
+		case ">":
+			return NewRatingPredicate(rating + 1), nil
+		case "<=":
+			return NewRatingRangePredicate(0, rating), nil
+		case "<":
+			return NewRatingRangePredicate(0, rating-1), nil
+		case "=":
+			return NewRatingRangePredicate(rating, rating), nil
+		}
+	}
+	return nil, qp.errorAt(field, "unknown field %q: use ingredient, name, or rating", field.text)
+}
+
+// newTextPredicate matches text in a recipe's name or ingredients.
+func newTextPredicate(text string) Predicate {
+	return NewOrPredicate(NewNamePredicate(text), NewIngredientPredicate(text))
+}
@@ -0,0 +1,118 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	recipes := []*sozzler.Recipe{
+		{Name: "Gimlet", Rating: 4, Components: []sozzler.Component{{Ingredient: "Gin"}, {Ingredient: "Lime Juice"}}},
+		{Name: "Gin Fizz", Rating: 3, Components: []sozzler.Component{{Ingredient: "Gin"}, {Ingredient: "Lemon Juice"}, {Ingredient: "Egg White"}}},
+		{Name: "Clover Club", Rating: 5, Components: []sozzler.Component{{Ingredient: "Gin"}, {Ingredient: "Lime Juice"}, {Ingredient: "Egg White"}}},
+		{Name: "Daiquiri", Rating: 5, Components: []sozzler.Component{{Ingredient: "Light Rum"}, {Ingredient: "Lime Juice"}}},
+		{Name: "Mai Tai", Rating: 2, Components: []sozzler.Component{{Ingredient: "Jamaican Rum"}, {Ingredient: "Orgeat"}}},
+	}
+
+	testCases := []struct {
+		query string
+		want  []string
+	}{
+		{query: "ingredient:gin", want: []string{"Gimlet", "Gin Fizz", "Clover Club"}},
+		{query: "ingredient:gin and ingredient:lime and not ingredient:egg and rating>=4", want: []string{"Gimlet"}},
+		{query: "i:gin i:lime", want: []string{"Gimlet", "Clover Club"}},
+		{query: "i:rum or n:fizz", want: []string{"Gin Fizz", "Daiquiri", "Mai Tai"}},
+		{query: "i:rum or n:fizz and rating>3", want: []string{"Daiquiri", "Mai Tai"}},
+		{query: "(i:rum or n:fizz) and rating>2", want: []string{"Gin Fizz", "Daiquiri"}},
+		{query: `name:"mai tai"`, want: []string{"Mai Tai"}},
+		{query: "NOT lime", want: []string{"Gin Fizz", "Mai Tai"}},
+		{query: "not not lime", want: []string{"Gimlet", "Clover Club", "Daiquiri"}},
+		{query: "rating=5", want: []string{"Clover Club", "Daiquiri"}},
+		{query: "rating<3", want: []string{"Mai Tai"}},
+		{query: "r<=3", want: []string{"Gin Fizz", "Mai Tai"}},
+		{query: "rating:5", want: []string{"Clover Club", "Daiquiri"}},
+		{query: "gin", want: []string{"Gimlet", "Gin Fizz", "Clover Club"}},
+		{query: "'egg white'", want: []string{"Gin Fizz", "Clover Club"}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.query, func(t *testing.T) {
+			p, err := sozzler.ParseQuery(tC.query)
+			require.NoError(t, err)
+
+			var got []string
+			for _, r := range recipes {
+				if _, ok := p.Match(r); ok {
+					got = append(got, r.Name)
+				}
+			}
+			assert.Equal(t, tC.want, got)
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	testCases := []struct {
+		query   string
+		wantPos int
+		wantMsg string
+	}{
+		{query: "", wantPos: 0, wantMsg: "empty query"},
+		{query: "ingredient: and rum", wantPos: 12, wantMsg: `expected a value after "ingredient:"`},
+		{query: "gin and", wantPos: 7, wantMsg: "expected a term, found end of query"},
+		{query: "and gin", wantPos: 0, wantMsg: `expected a term before "and"`},
+		{query: "gin or or rum", wantPos: 7, wantMsg: `expected a term before "or"`},
+		{query: "(gin or rum", wantPos: 0, wantMsg: "unmatched ("},
+		{query: "gin)", wantPos: 3, wantMsg: "unmatched )"},
+		{query: "colour:red", wantPos: 0, wantMsg: `unknown field "colour": use ingredient, name, or rating`},
+		{query: "rating>=lots", wantPos: 8, wantMsg: `rating must be a whole number, not "lots"`},
+		{query: "name>=foo", wantPos: 4, wantMsg: "use name:"},
+		{query: `gin and name:"mai tai`, wantPos: 13, wantMsg: "unterminated quote"},
+		{query: "i:gin ()", wantPos: 7, wantMsg: `expected a term, found ")"`},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.query, func(t *testing.T) {
+			_, err := sozzler.ParseQuery(tC.query)
+			var queryErr *sozzler.QueryError
+			require.ErrorAs(t, err, &queryErr)
+			assert.Equal(t, tC.wantPos, queryErr.Pos)
+			assert.Equal(t, tC.wantMsg, queryErr.Msg)
+		})
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	_, err := sozzler.ParseQuery("i:añejo and rating>=x")
+	require.Error(t, err)
+	assert.Equal(t, "invalid query: rating must be a whole number, not \"x\"\n  i:añejo and rating>=x\n                      ^", err.Error())
+}
+
+func TestCompoundPredicates(t *testing.T) {
+	r := &sozzler.Recipe{Name: "Gimlet", Rating: 4, Components: []sozzler.Component{{Ingredient: "Gin"}, {Ingredient: "Lime Juice"}}}
+
+	match, ok := sozzler.NewAndPredicate(sozzler.NewIngredientPredicate("gin"), sozzler.NewRatingPredicate(4)).Match(r)
+	assert.True(t, ok)
+	assert.Equal(t, "Gin (Ingredient), 🫒🫒🫒🫒 (Rating)", match)
+
+	_, ok = sozzler.NewAndPredicate(sozzler.NewIngredientPredicate("gin"), sozzler.NewRatingPredicate(5)).Match(r)
+	assert.False(t, ok)
+
+	match, ok = sozzler.NewOrPredicate(sozzler.NewIngredientPredicate("rum"), sozzler.NewNamePredicate("gim")).Match(r)
+	assert.True(t, ok)
+	assert.Equal(t, "Gimlet (Name)", match)
+
+	match, ok = sozzler.NewNotPredicate(sozzler.NewIngredientPredicate("egg")).Match(r)
+	assert.True(t, ok)
+	assert.Equal(t, "", match)
+
+	catalog := sozzler.RecipeCatalog{Recipes: []*sozzler.Recipe{r}}
+	results := catalog.Search([]sozzler.Predicate{
+		sozzler.NewAndPredicate(sozzler.NewIngredientPredicate("lime"), sozzler.NewNotPredicate(sozzler.NewIngredientPredicate("egg"))),
+	})
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Matches, 1, "compound predicates report their parts")
+	assert.Equal(t, "Lime Juice", results[0].Matches[0].Match)
+	assert.Equal(t, "Ingredient", results[0].Matches[0].Predicate.Name())
+}
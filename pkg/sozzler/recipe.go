@@ -1,20 +1,37 @@
 package sozzler
 
 import (
+	"fmt"
+	"math/big"
 	"sort"
+	"time"
 )
 
+// Field order here is the canonical key order of recipe files.
+
 type Component struct {
 	Ingredient string   `yaml:"ingredient"`
-	Quantity   Quantity `yaml:"quantity"`
-	Unit       string   `yaml:"unit"`
+	Quantity   Quantity `yaml:"quantity,omitempty"`
+	Unit       string   `yaml:"unit,omitempty"`
 }
 
 type Recipe struct {
-	Name       string      `yaml:"name"`
-	Notes      string      `yaml:"text"`
+	Name   string `yaml:"name"`
+	Rating int    `yaml:"rating"`
+	// Servings is how many drinks the recipe makes, if it says. Recipes that
+	// don't say make one.
+	Servings int `yaml:"servings,omitempty"`
+	// Method is how the drink is mixed, if the recipe says.
+	Method     Method      `yaml:"method,omitempty"`
 	Components []Component `yaml:"components"`
-	Rating     int         `yaml:"rating"`
+	Notes      string      `yaml:"text,omitempty"`
+
+	// Path is the file the recipe was loaded from or saved to, if any. Recipes
+	// loaded with LoadFS have paths relative to their fs.FS.
+	Path string `yaml:"-"`
+	// Added is when the recipe was added to its directory, as far as anyone
+	// can tell: its file's modification time.
+	Added time.Time `yaml:"-"`
 }
 
 func (r *Recipe) FancyRating() string {
@@ -25,23 +42,72 @@ func (r *Recipe) FancyRating() string {
 	return rating
 }
 
+// Yield returns how many drinks the recipe makes.
+func (r *Recipe) Yield() int {
+	if r.Servings > 0 {
+		return r.Servings
+	}
+	return 1
+}
+
+// Scale returns a copy of the recipe with every component scaled by factor and
+// rounded to an amount a bartender can measure. It fails if an amount gets too
+// large or too precise to be a Quantity.
+func (r *Recipe) Scale(factor *big.Rat) (*Recipe, error) {
+	scaled := *r
+	scaled.Components = make([]Component, len(r.Components))
+	for i, c := range r.Components {
+		if factor.Cmp(big.NewRat(1, 1)) != 0 {
+			q, err := c.Quantity.Scale(factor)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't scale %q in %q: %w", c.Ingredient, r.Name, err)
+			}
+			c.Quantity = q.Measurable()
+		}
+		scaled.Components[i] = c
+	}
+	return &scaled, nil
+}
+
+// Volume returns the component's quantity in milliliters, if it's measured in a
+// unit of volume.
+func (c Component) Volume() (float64, bool) {
+	u, ok := LookupUnit(c.Unit)
+	if !ok || u.Dimension != Volume {
+		return 0, false
+	}
+	return c.Quantity.Float() * u.Factor, true
+}
+
+// FancyOrder sorts components for display: those measured by volume first,
+// largest first, then those measured in other units, then unmeasured ones like
+// garnishes.
 func FancyOrder(components []Component) []Component {
-	sort.Slice(components, func(i, j int) bool {
+	sort.SliceStable(components, func(i, j int) bool {
 		ci, cj := components[i], components[j]
-		if ci.Unit == cj.Unit {
-			if ci.Quantity == cj.Quantity {
-				return ci.Ingredient < cj.Ingredient
-			}
-			return ci.Quantity.Float() > cj.Quantity.Float()
+
+		vi, iok := ci.Volume()
+		vj, jok := cj.Volume()
+		if iok != jok {
+			return iok
 		}
-		if ci.Unit == "" {
-			return false
+		if iok && vi != vj {
+			return vi > vj
+		}
+
+		if ci.Unit != cj.Unit {
+			if ci.Unit == "" {
+				return false
+			}
+			if cj.Unit == "" {
+				return true
+			}
+			return ci.Unit < cj.Unit
 		}
-		if cj.Unit == "" {
-			return true
+		if cmp := ci.Quantity.Cmp(cj.Quantity); cmp != 0 {
+			return cmp > 0
 		}
-		// FIXME: have some conversion table that normalizes all quantities into grams
-		return false
+		return ci.Ingredient < cj.Ingredient
 	})
 
 	return components
@@ -0,0 +1,128 @@
+package sozzler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrRecipeExists = errors.New("recipe already exists")
+	ErrNoRecipeDir  = errors.New("catalog has no recipe directory")
+)
+
+// Save writes recipe to a new file in rc.Dir, named by RecipeFilename, and adds
+// it to the catalog, setting its Path. It fails with ErrRecipeExists if the
+// catalog already has a recipe by that name, or the file already exists.
+func (rc *RecipeCatalog) Save(recipe *Recipe) error {
+	return rc.save(recipe, false)
+}
+
+// Replace is Save, but overwrites any existing recipe by the same name, in the
+// file that recipe was loaded from.
+func (rc *RecipeCatalog) Replace(recipe *Recipe) error {
+	return rc.save(recipe, true)
+}
+
+func (rc *RecipeCatalog) save(recipe *Recipe, overwrite bool) error {
+	base := RecipeFilename(recipe.Name)
+	if base == "" {
+		return fmt.Errorf("couldn't save recipe %q: recipe needs a name", recipe.Name)
+	}
+
+	var filename string
+	if existing, ok := rc.Find(recipe.Name); ok && overwrite && existing.Path != "" {
+		filename = existing.Path
+	} else if rc.Dir != "" {
+		filename = filepath.Join(rc.Dir, base)
+	} else {
+		return ErrNoRecipeDir
+	}
+
+	if !overwrite {
+		if _, ok := rc.Find(recipe.Name); ok {
+			return fmt.Errorf("couldn't save recipe %q: %w", recipe.Name, ErrRecipeExists)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeRecipe(&buf, recipe); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filename, buf.Bytes(), overwrite); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("couldn't save recipe %q to %q: %w", recipe.Name, filename, ErrRecipeExists)
+		}
+		return fmt.Errorf("couldn't save recipe %q: %w", recipe.Name, err)
+	}
+
+	recipe.Path = filename
+	if recipe.Added.IsZero() {
+		recipe.Added = time.Now()
+	}
+	rc.add(recipe)
+	return nil
+}
+
+// RecipeFilename returns the file name for a recipe called name: the name with
+// a .yaml extension, after replacing path separators and characters that some
+// filesystems don't allow with "-", folding curly apostrophes to straight ones,
+// and trimming leading dots so the file isn't hidden. Characters like "#", "'",
+// and accented letters are kept. It returns "" if nothing of name is left.
+func RecipeFilename(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r == '’' || r == '‘':
+			b.WriteRune('\'')
+		case strings.ContainsRune(`/\:*?"<>|`, r), unicode.IsControl(r):
+			b.WriteRune('-')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	stem := strings.TrimLeft(strings.TrimSpace(b.String()), ".")
+	stem = strings.TrimRight(stem, ". ")
+	if stem == "" {
+		return ""
+	}
+	return stem + ".yaml"
+}
+
+// writeFileAtomic writes data to a temporary file next to filename and renames
+// it into place, so readers never see a partly written file. Unless overwrite
+// is set, the temporary file is linked into place instead, which fails with
+// fs.ErrExist if filename already exists rather than replacing it.
+func writeFileAtomic(filename string, data []byte, overwrite bool) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	if !overwrite {
+		return os.Link(tmp.Name(), filename)
+	}
+	return os.Rename(tmp.Name(), filename)
+}
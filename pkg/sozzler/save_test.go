@@ -0,0 +1,98 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeFilename(t *testing.T) {
+	testCases := []struct {
+		given string
+		want  string
+	}{
+		{given: "Aviation", want: "Aviation.yaml"},
+		{given: "Corpse Reviver #23", want: "Corpse Reviver #23.yaml"},
+		{given: "Bee's Knees", want: "Bee's Knees.yaml"},
+		{given: "Bee’s Knees", want: "Bee's Knees.yaml"},
+		{given: "Vieux Carré", want: "Vieux Carré.yaml"},
+		{given: "AC/DC", want: "AC-DC.yaml"},
+		{given: `What? "Me"`, want: "What- -Me-.yaml"},
+		{given: "  ..hidden  ", want: "hidden.yaml"},
+		{given: "...", want: ""},
+		{given: "", want: ""},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.given, func(t *testing.T) {
+			assert.Equal(t, tC.want, sozzler.RecipeFilename(tC.given))
+		})
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "Aviation.yaml"), "name: Aviation\nrating: 3\n")
+
+	var catalog sozzler.RecipeCatalog
+	require.NoError(t, catalog.Load(dir))
+
+	daiquiri := &sozzler.Recipe{
+		Name:       "Daiquiri",
+		Components: []sozzler.Component{*component("rum", "2", "oz")},
+	}
+	require.NoError(t, catalog.Save(daiquiri))
+	assert.Equal(t, filepath.Join(dir, "Daiquiri.yaml"), daiquiri.Path)
+
+	_, ok := catalog.Find("daiquiri")
+	assert.True(t, ok)
+
+	err := catalog.Save(&sozzler.Recipe{Name: "aviation", Rating: 5})
+	assert.ErrorIs(t, err, sozzler.ErrRecipeExists)
+
+	require.NoError(t, catalog.Replace(&sozzler.Recipe{Name: "aviation", Rating: 5}))
+
+	var reloaded sozzler.RecipeCatalog
+	require.NoError(t, reloaded.Load(dir))
+	assert.Len(t, reloaded.Recipes, 2)
+
+	aviation, ok := reloaded.Find("Aviation")
+	require.True(t, ok)
+	assert.Equal(t, 5, aviation.Rating)
+	assert.Equal(t, filepath.Join(dir, "Aviation.yaml"), aviation.Path)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "no temporary files are left behind")
+}
+
+func TestSaveNoDir(t *testing.T) {
+	var catalog sozzler.RecipeCatalog
+	assert.ErrorIs(t, catalog.Save(&sozzler.Recipe{Name: "Daiquiri"}), sozzler.ErrNoRecipeDir)
+}
+
+func TestSaveExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	var catalog sozzler.RecipeCatalog
+	require.NoError(t, catalog.Load(dir))
+
+	// the file turns up after the catalog is loaded, as if saved by someone else
+	filename := filepath.Join(dir, "Daiquiri.yaml")
+	writeFile(t, filename, "name: Daiquiri\nrating: 4\n")
+
+	err := catalog.Save(&sozzler.Recipe{Name: "Daiquiri", Rating: 1})
+	assert.ErrorIs(t, err, sozzler.ErrRecipeExists)
+	_, ok := catalog.Find("Daiquiri")
+	assert.False(t, ok)
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "name: Daiquiri\nrating: 4\n", string(data), "the existing file is untouched")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
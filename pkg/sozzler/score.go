@@ -0,0 +1,110 @@
+package sozzler
+
+import "strings"
+
+// Scores of how well a search term matches some text, best first. A
+// NamePredicate's scores count NameWeight times as much.
+const (
+	ExactScore     = 1.0
+	PrefixScore    = 0.75
+	SubstringScore = 0.5
+	FuzzyScore     = 0.25
+
+	NameWeight = 2.0
+)
+
+// ScoredPredicate is a Predicate that says how well a recipe matches, so the
+// best matches can be listed first. Matches of predicates that don't say
+// score 1.
+type ScoredPredicate interface {
+	Predicate
+	Score(*Recipe) (string, float64, bool)
+}
+
+// FuzzyPredicate is a Predicate that can also match with a typo or two. Search
+// falls back to its Fuzzy form when nothing matches as typed.
+type FuzzyPredicate interface {
+	Predicate
+	Fuzzy() Predicate
+}
+
+// scoreText scores how well term matches text, both lower case: exactly, at
+// the start, anywhere, or, if fuzzy is set, with a typo or two in one of its
+// words. It returns 0 if term doesn't match.
+func scoreText(term, text string, fuzzy bool) float64 {
+	switch {
+	case term == "":
+		return SubstringScore
+	case text == term:
+		return ExactScore
+	case strings.HasPrefix(text, term):
+		return PrefixScore
+	case strings.Contains(text, term):
+		return SubstringScore
+	case fuzzy && fuzzyMatch(term, text):
+		return FuzzyScore
+	}
+	return 0
+}
+
+// fuzzyMatch reports whether term is within a typo or two of text, or of a run
+// of its words as long as term. Terms shorter than 4 letters don't match
+// fuzzily, as nearly everything is a typo or two away from them.
+func fuzzyMatch(term, text string) bool {
+	allowed := typos(term)
+	if allowed == 0 {
+		return false
+	}
+	if editDistance(term, text) <= allowed {
+		return true
+	}
+
+	words := strings.Fields(text)
+	n := len(strings.Fields(term))
+	for i := 0; i+n <= len(words); i++ {
+		if editDistance(term, strings.Join(words[i:i+n], " ")) <= allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// typos is how many typos a term can have and still match.
+func typos(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is the number of insertions, deletions, substitutions, and
+// swaps of adjacent letters that turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// d[i][j] is the distance between ra[:i] and rb[:j]
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,337 @@
+package sozzler
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ShoppingOptions say what a shopping list is for.
+type ShoppingOptions struct {
+	// Servings is how many drinks of each recipe to shop for. Zero shops for
+	// each recipe as it's written.
+	Servings int
+	// Inventory, if set, is taken off what's needed.
+	Inventory *Inventory
+	// Ingredients groups the list, and knows the size of bottles and how much
+	// juice a fruit makes. Nil uses the defaults.
+	Ingredients *IngredientDB
+}
+
+// ShoppingItem is an ingredient to buy.
+type ShoppingItem struct {
+	// Ingredient is the ingredient as the first recipe needing it writes it.
+	Ingredient string
+	// Group is where the ingredient is found in a store, like Spirits or
+	// Citrus.
+	Group string
+	// Quantity and Unit are how much to buy. Quantity is zero for unmeasured
+	// ingredients, like garnishes.
+	Quantity Quantity
+	Unit     string
+	// Bottles is how many bottles of BottleSize ml to buy, for ingredients
+	// sold by the bottle.
+	Bottles    int
+	BottleSize float64
+	// Fruit is how many of Source to buy to make the ingredient, like 6 limes
+	// for lime juice.
+	Fruit  int
+	Source string
+	// Drinks is how many drinks the ingredient is for, and Recipes are their
+	// names.
+	Drinks  int
+	Recipes []string
+}
+
+// shoppingGroups are the groups of a shopping list, in order, by the top
+// category of their ingredients. Ingredients with other top categories come
+// after, then those not in the ingredient database, in OtherGroup.
+var shoppingGroups = []struct{ category, group string }{
+	{"spirit", "Spirits"},
+	{"liqueur", "Liqueurs"},
+	{"wine", "Wine"},
+	{"bitters", "Bitters"},
+	{"juice", "Citrus"},
+	{"fruit", "Fruit"},
+	{"nut", "Nuts"},
+	{"syrup", "Syrups"},
+	{"sugar", "Syrups"},
+	{"mixer", "Mixers"},
+	{"egg", "Eggs and dairy"},
+	{"dairy", "Eggs and dairy"},
+	{"herb", "Herbs and spices"},
+	{"spice", "Herbs and spices"},
+	{"garnish", "Garnish"},
+	{"ice", "Ice"},
+}
+
+// OtherGroup is the group of ingredients that aren't in the ingredient
+// database.
+const OtherGroup = "Other"
+
+// shoppingNeed adds up how much of one ingredient, measured one way, is
+// needed.
+type shoppingNeed struct {
+	item ShoppingItem
+	in   *Ingredient
+	// ml is the amount needed, for ingredients measured by volume, and byUnit
+	// is how much of it is written in each unit.
+	ml     *big.Rat
+	byUnit map[string]*big.Rat
+	// total is the amount needed in item.Unit, for ingredients measured
+	// another way.
+	total Quantity
+}
+
+// ShoppingList adds up the ingredients needed to make recipes, less what's in
+// the inventory. The same ingredient measured in different units of volume is
+// added up in the unit it's measured in most. Ranges, like 1-2 dashes, are
+// shopped for at the top of the range. Water and ice are never on the list.
+// Items are in the order of their groups, then by ingredient. It fails if an
+// amount gets too large or too precise to be a Quantity.
+func ShoppingList(recipes []*Recipe, opts ShoppingOptions) ([]ShoppingItem, error) {
+	db := opts.Ingredients
+	if db == nil {
+		db = defaultDB
+	}
+	inventory := opts.Inventory
+	if inventory == nil {
+		inventory = &Inventory{}
+	}
+
+	var needs []*shoppingNeed
+	byKey := make(map[string]*shoppingNeed)
+	for _, r := range recipes {
+		drinks := r.Yield()
+		if opts.Servings > 0 {
+			drinks = opts.Servings
+		}
+		factor := big.NewRat(int64(drinks), int64(r.Yield()))
+
+		for _, c := range r.Components {
+			in, resolved := db.Resolve(c.Ingredient)
+			key := normalizeIngredient(c.Ingredient)
+			if resolved {
+				key = in.Name
+			}
+			u, measured := LookupUnit(c.Unit)
+			volume := measured && u.Dimension == Volume && !c.Quantity.IsZero()
+			switch {
+			case c.Quantity.IsZero():
+				key += "\x00"
+			case volume:
+				key += "\x00volume"
+			default:
+				key += "\x00" + c.Unit
+			}
+
+			need, ok := byKey[key]
+			if !ok {
+				need = &shoppingNeed{
+					item:   ShoppingItem{Ingredient: c.Ingredient, Group: db.shoppingGroup(in)},
+					ml:     new(big.Rat),
+					byUnit: make(map[string]*big.Rat),
+				}
+				if resolved {
+					need.in = in
+				}
+				if !volume && !c.Quantity.IsZero() {
+					need.item.Unit = c.Unit
+				}
+				byKey[key] = need
+				needs = append(needs, need)
+			}
+
+			if len(need.item.Recipes) == 0 || need.item.Recipes[len(need.item.Recipes)-1] != r.Name {
+				need.item.Recipes = append(need.item.Recipes, r.Name)
+				need.item.Drinks += drinks
+			}
+
+			q, err := c.Quantity.Max().Scale(factor)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't shop for %q in %q: %w", c.Ingredient, r.Name, err)
+			}
+			switch {
+			case volume:
+				ml := new(big.Rat).Mul(q.Rat(), u.size)
+				need.ml.Add(need.ml, ml)
+				if need.byUnit[u.Name] == nil {
+					need.byUnit[u.Name] = new(big.Rat)
+				}
+				need.byUnit[u.Name].Add(need.byUnit[u.Name], ml)
+			case !c.Quantity.IsZero():
+				if need.total, err = need.total.Add(q); err != nil {
+					return nil, fmt.Errorf("couldn't shop for %q: %w", c.Ingredient, err)
+				}
+			}
+		}
+	}
+
+	var items []ShoppingItem
+	for _, need := range needs {
+		item, ok, err := need.shop(db, inventory)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't shop for %q: %w", item.Ingredient, err)
+		}
+		if ok {
+			items = append(items, item)
+		}
+	}
+
+	order := func(group string) int {
+		for i, g := range shoppingGroups {
+			if g.group == group {
+				return i
+			}
+		}
+		if group == OtherGroup {
+			return len(shoppingGroups) + 1
+		}
+		return len(shoppingGroups)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if oi, oj := order(items[i].Group), order(items[j].Group); oi != oj {
+			return oi < oj
+		}
+		if items[i].Group != items[j].Group {
+			return items[i].Group < items[j].Group
+		}
+		return strings.ToLower(items[i].Ingredient) < strings.ToLower(items[j].Ingredient)
+	})
+	return items, nil
+}
+
+// shop returns what to buy for need, less what's in inventory, and false if
+// nothing needs buying.
+func (need *shoppingNeed) shop(db *IngredientDB, inventory *Inventory) (ShoppingItem, bool, error) {
+	item := need.item
+	stock, staple := inventory.stock(db, item.Ingredient)
+	if staple {
+		return item, false, nil
+	}
+	for _, s := range stock {
+		if s.Quantity.IsZero() {
+			// there's some, and no telling how much
+			return item, false, nil
+		}
+	}
+
+	switch {
+	case need.ml.Sign() > 0:
+		ml := new(big.Rat).Set(need.ml)
+		for _, s := range stock {
+			if u, ok := LookupUnit(s.Unit); ok && u.Dimension == Volume {
+				ml.Sub(ml, new(big.Rat).Mul(s.Quantity.Max().Rat(), u.size))
+			}
+		}
+		if ml.Sign() <= 0 {
+			return item, false, nil
+		}
+
+		// the unit most of it is measured in, or the largest, on a tie
+		var most *big.Rat
+		for name, amount := range need.byUnit {
+			u, _ := LookupUnit(name)
+			if c := cmpNil(amount, most); c > 0 || (c == 0 && u.Factor > unitOrZero(item.Unit).Factor) {
+				most, item.Unit = amount, name
+			}
+		}
+		q, err := quantity(ml)
+		if err != nil {
+			return item, false, err
+		}
+		q, _ = Convert(q, "ml", item.Unit)
+		if unitOrZero(item.Unit).System == Metric {
+			q = q.Round(big.NewRat(1, 1))
+			q.decimal = true
+		} else {
+			q = q.Measurable()
+		}
+		item.Quantity = q
+
+		mlf, _ := ml.Float64()
+		if need.in != nil && need.in.Bottle > 0 {
+			item.Bottles, item.BottleSize = ceilDiv(mlf, need.in.Bottle), need.in.Bottle
+		}
+		if need.in != nil && need.in.Source != "" && need.in.Yield > 0 {
+			item.Fruit, item.Source = ceilDiv(mlf, need.in.Yield), need.in.Source
+		}
+	case !need.total.IsZero():
+		left := need.total.Rat()
+		for _, s := range stock {
+			if s.Unit == item.Unit {
+				left.Sub(left, s.Quantity.Max().Rat())
+			}
+		}
+		if left.Sign() <= 0 {
+			return item, false, nil
+		}
+		q, err := quantity(left)
+		if err != nil {
+			return item, false, err
+		}
+		item.Quantity = q.Measurable()
+	default:
+		if len(stock) > 0 {
+			return item, false, nil
+		}
+	}
+	return item, true, nil
+}
+
+// shoppingGroup returns the group of the shopping list in goes in.
+func (db *IngredientDB) shoppingGroup(in *Ingredient) string {
+	if in == nil {
+		return OtherGroup
+	}
+	for depth := 0; depth <= len(db.entries); depth++ {
+		parent, ok := db.Category(in)
+		if !ok {
+			break
+		}
+		in = parent
+	}
+	for _, g := range shoppingGroups {
+		if strings.EqualFold(g.category, in.Name) {
+			return g.group
+		}
+	}
+	first, size := utf8.DecodeRuneInString(in.Name)
+	if size == 0 {
+		return OtherGroup
+	}
+	return string(unicode.ToUpper(first)) + in.Name[size:]
+}
+
+// cmpNil compares a and b like big.Rat.Cmp, with nil less than anything.
+func cmpNil(a, b *big.Rat) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case b == nil:
+		return 1
+	case a == nil:
+		return -1
+	}
+	return a.Cmp(b)
+}
+
+// unitOrZero looks up a unit, returning the zero Unit if it isn't known.
+func unitOrZero(name string) *Unit {
+	if u, ok := LookupUnit(name); ok {
+		return u
+	}
+	return &Unit{}
+}
+
+// ceilDiv returns a/b rounded up.
+func ceilDiv(a, b float64) int {
+	n := int(a / b)
+	if float64(n)*b < a {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,88 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShoppingList(t *testing.T) {
+	daiquiri := &sozzler.Recipe{Name: "Daiquiri", Components: []sozzler.Component{
+		*component("Light Rum", "2", "oz"),
+		*component("Lime Juice", "3/4", "oz"),
+		*component("Simple Syrup", "3/4", "oz"),
+		{Ingredient: "Lime Wheel"},
+	}}
+	punch := &sozzler.Recipe{Name: "Rum Punch", Servings: 4, Components: []sozzler.Component{
+		*component("White Rum", "240", "ml"),
+		*component("Lime Juice", "4", "oz"),
+		*component("Angostura Bitters", "2-4", "dash"),
+		*component("Mystery Cordial", "2", "oz"),
+		*component("Water", "4", "oz"),
+		{Ingredient: "Lime Wheel"},
+	}}
+
+	type got struct {
+		Group, Item    string
+		Bottles, Fruit int
+		Drinks         int
+	}
+	shop := func(opts sozzler.ShoppingOptions) []got {
+		var gots []got
+		for _, item := range must(sozzler.ShoppingList([]*sozzler.Recipe{daiquiri, punch}, opts)) {
+			gots = append(gots, got{
+				Group:   item.Group,
+				Item:    item.Quantity.String() + " " + item.Unit + " " + item.Ingredient,
+				Bottles: item.Bottles,
+				Fruit:   item.Fruit,
+				Drinks:  item.Drinks,
+			})
+		}
+		return gots
+	}
+
+	assert.Equal(t, []got{
+		{Group: "Spirits", Item: "1430 ml Light Rum", Bottles: 2, Drinks: 24},
+		{Group: "Bitters", Item: "12 dash Angostura Bitters", Bottles: 1, Drinks: 12},
+		{Group: "Citrus", Item: "21 oz Lime Juice", Fruit: 21, Drinks: 24},
+		{Group: "Syrups", Item: "9 oz Simple Syrup", Drinks: 12},
+		{Group: "Garnish", Item: "  Lime Wheel", Drinks: 24},
+		{Group: sozzler.OtherGroup, Item: "6 oz Mystery Cordial", Drinks: 12},
+	}, shop(sozzler.ShoppingOptions{Servings: 12}))
+
+	inventory := &sozzler.Inventory{Items: []sozzler.Component{
+		*component("Light Rum", "750", "ml"),
+		*component("Lime Juice", "1", "cup"),
+		{Ingredient: "Simple Syrup"},
+		{Ingredient: "Angostura Bitters", Quantity: *must(sozzler.ParseQuantity("0"))},
+	}}
+	assert.Equal(t, []got{
+		{Group: "Spirits", Item: "680 ml Light Rum", Bottles: 1, Drinks: 24},
+		{Group: "Bitters", Item: "12 dash Angostura Bitters", Bottles: 1, Drinks: 12},
+		{Group: "Citrus", Item: "13 oz Lime Juice", Fruit: 13, Drinks: 24},
+		{Group: "Garnish", Item: "  Lime Wheel", Drinks: 24},
+		{Group: sozzler.OtherGroup, Item: "6 oz Mystery Cordial", Drinks: 12},
+	}, shop(sozzler.ShoppingOptions{Servings: 12, Inventory: inventory}))
+
+	assert.Equal(t, []got{
+		{Group: "Spirits", Item: "299 ml Light Rum", Bottles: 1, Drinks: 5},
+		{Group: "Bitters", Item: "4 dash Angostura Bitters", Bottles: 1, Drinks: 4},
+		{Group: "Citrus", Item: "4 3/4 oz Lime Juice", Fruit: 5, Drinks: 5},
+		{Group: "Syrups", Item: "3/4 oz Simple Syrup", Drinks: 1},
+		{Group: "Garnish", Item: "  Lime Wheel", Drinks: 5},
+		{Group: sozzler.OtherGroup, Item: "2 oz Mystery Cordial", Drinks: 4},
+	}, shop(sozzler.ShoppingOptions{}), "as written")
+}
+
+func TestShoppingListGroupName(t *testing.T) {
+	db := sozzler.DefaultIngredients()
+	db.Add(&sozzler.Ingredient{Name: "éaux"}, &sozzler.Ingredient{Name: "poire williams", Category: "éaux"})
+	recipe := &sozzler.Recipe{Name: "Pear", Components: []sozzler.Component{*component("Poire Williams", "2", "oz")}}
+
+	items, err := sozzler.ShoppingList([]*sozzler.Recipe{recipe}, sozzler.ShoppingOptions{Ingredients: db})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Éaux", items[0].Group)
+}
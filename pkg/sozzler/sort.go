@@ -0,0 +1,86 @@
+package sozzler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortOrder is an order to list recipes in.
+type SortOrder string
+
+const (
+	// ByName sorts alphabetically, ignoring case.
+	ByName SortOrder = "name"
+	// ByRating sorts the best rated recipes first.
+	ByRating SortOrder = "rating"
+	// ByMatch sorts the best matching recipes first, by score, then by number
+	// of matches.
+	ByMatch SortOrder = "match"
+	// ByIngredients sorts the recipes with the fewest ingredients first.
+	ByIngredients SortOrder = "ingredients"
+	// ByAdded sorts the most recently added recipes first.
+	ByAdded SortOrder = "added"
+)
+
+// SortOrders are the orders recipes can be sorted in.
+var SortOrders = []SortOrder{ByName, ByRating, ByMatch, ByIngredients, ByAdded}
+
+func ParseSortOrder(s string) (SortOrder, error) {
+	for _, o := range SortOrders {
+		if strings.EqualFold(s, string(o)) {
+			return o, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sort order %q: use name, rating, match, ingredients, or added", s)
+}
+
+// SortResults sorts results in order, or the reverse of it. Ties are broken by
+// name, then by path, so the order is the same every time.
+func SortResults(results []SearchResult, order SortOrder, reverse bool) {
+	less := func(a, b SearchResult) bool {
+		switch order {
+		case ByRating:
+			if a.Recipe.Rating != b.Recipe.Rating {
+				return a.Recipe.Rating > b.Recipe.Rating
+			}
+		case ByMatch:
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+			if len(a.Matches) != len(b.Matches) {
+				return len(a.Matches) > len(b.Matches)
+			}
+		case ByIngredients:
+			if len(a.Recipe.Components) != len(b.Recipe.Components) {
+				return len(a.Recipe.Components) < len(b.Recipe.Components)
+			}
+		case ByAdded:
+			if !a.Recipe.Added.Equal(b.Recipe.Added) {
+				return a.Recipe.Added.After(b.Recipe.Added)
+			}
+		}
+		if an, bn := strings.ToLower(a.Recipe.Name), strings.ToLower(b.Recipe.Name); an != bn {
+			return an < bn
+		}
+		return a.Recipe.Path < b.Recipe.Path
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if reverse {
+			return less(results[j], results[i])
+		}
+		return less(results[i], results[j])
+	})
+}
+
+// SortRecipes sorts recipes like SortResults. Sorting by match sorts by name.
+func SortRecipes(recipes []*Recipe, order SortOrder, reverse bool) {
+	results := make([]SearchResult, len(recipes))
+	for i, r := range recipes {
+		results[i] = SearchResult{Recipe: r}
+	}
+	SortResults(results, order, reverse)
+	for i, r := range results {
+		recipes[i] = r.Recipe
+	}
+}
@@ -0,0 +1,100 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortResults(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	components := func(n int) []sozzler.Component { return make([]sozzler.Component, n) }
+	results := func() []sozzler.SearchResult {
+		match := sozzler.MatchResult{Predicate: sozzler.NewNamePredicate(""), Match: "x"}
+		return []sozzler.SearchResult{
+			{Recipe: &sozzler.Recipe{Name: "negroni", Rating: 4, Components: components(3), Added: day(2)}, Matches: []sozzler.MatchResult{match}},
+			{Recipe: &sozzler.Recipe{Name: "Daiquiri", Rating: 5, Components: components(3), Added: day(1)}, Matches: []sozzler.MatchResult{match, match}},
+			{Recipe: &sozzler.Recipe{Name: "Mai Tai", Rating: 4, Components: components(6), Added: day(3)}, Matches: []sozzler.MatchResult{match, match}},
+			{Recipe: &sozzler.Recipe{Name: "Aviation", Rating: 3, Components: components(4)}, Matches: []sozzler.MatchResult{match}},
+		}
+	}
+
+	testCases := []struct {
+		order   sozzler.SortOrder
+		reverse bool
+		want    []string
+	}{
+		{order: sozzler.ByName, want: []string{"Aviation", "Daiquiri", "Mai Tai", "negroni"}},
+		{order: sozzler.ByName, reverse: true, want: []string{"negroni", "Mai Tai", "Daiquiri", "Aviation"}},
+		{order: sozzler.ByRating, want: []string{"Daiquiri", "Mai Tai", "negroni", "Aviation"}},
+		{order: sozzler.ByRating, reverse: true, want: []string{"Aviation", "negroni", "Mai Tai", "Daiquiri"}},
+		{order: sozzler.ByMatch, want: []string{"Daiquiri", "Mai Tai", "Aviation", "negroni"}},
+		{order: sozzler.ByIngredients, want: []string{"Daiquiri", "negroni", "Aviation", "Mai Tai"}},
+		{order: sozzler.ByAdded, want: []string{"Mai Tai", "negroni", "Daiquiri", "Aviation"}},
+	}
+	for _, tC := range testCases {
+		name := string(tC.order)
+		if tC.reverse {
+			name += " reversed"
+		}
+		t.Run(name, func(t *testing.T) {
+			got := results()
+			sozzler.SortResults(got, tC.order, tC.reverse)
+
+			var names []string
+			for _, r := range got {
+				names = append(names, r.Recipe.Name)
+			}
+			assert.Equal(t, tC.want, names)
+
+			var recipes []*sozzler.Recipe
+			for _, r := range results() {
+				recipes = append(recipes, r.Recipe)
+			}
+			if tC.order != sozzler.ByMatch {
+				sozzler.SortRecipes(recipes, tC.order, tC.reverse)
+				names = nil
+				for _, r := range recipes {
+					names = append(names, r.Name)
+				}
+				assert.Equal(t, tC.want, names, "SortRecipes")
+			}
+		})
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	order, err := sozzler.ParseSortOrder("Rating")
+	require.NoError(t, err)
+	assert.Equal(t, sozzler.ByRating, order)
+
+	_, err = sozzler.ParseSortOrder("colour")
+	assert.Error(t, err)
+}
+
+func TestSearchOrder(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"Negroni", "Boulevardier", "Americano"} {
+		path := filepath.Join(dir, name+".yaml")
+		writeFile(t, path, "name: "+name+"\nrating: 3\ncomponents:\n  - ingredient: Campari\n")
+		added := time.Date(2025, 1, i+1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, os.Chtimes(path, added, added))
+	}
+
+	var catalog sozzler.RecipeCatalog
+	require.NoError(t, catalog.Load(dir))
+
+	results := catalog.Search([]sozzler.Predicate{sozzler.NewIngredientPredicate("campari")})
+	sozzler.SortResults(results, sozzler.ByAdded, false)
+
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Recipe.Name)
+	}
+	assert.Equal(t, []string{"Americano", "Boulevardier", "Negroni"}, names)
+}
@@ -0,0 +1,125 @@
+package sozzler
+
+import (
+	"sort"
+	"strings"
+)
+
+// Suggestion is an ingredient to buy, and the recipes buying it makes makeable,
+// along with the suggestions before it.
+type Suggestion struct {
+	Ingredient string
+	Recipes    []*Recipe
+	// Score is the sum of the ratings of Recipes. Unrated recipes count as 1.
+	Score int
+}
+
+// Suggest picks up to n ingredients to buy, one at a time, each the one that
+// makes the best rated new recipes makeable along with the ones before it. When
+// no single ingredient finishes a recipe, it picks the one that gets closest to
+// the most, but a suggestion that unlocks nothing is never returned.
+func (inv *Inventory) Suggest(db *IngredientDB, recipes []*Recipe, n int) []Suggestion {
+	have := &Inventory{Items: append([]Component(nil), inv.Items...)}
+
+	// missing is the components each unmade recipe still needs
+	missing := make(map[*Recipe][]Component)
+	for _, r := range recipes {
+		if len(r.Components) == 0 {
+			continue
+		}
+		if m := have.Missing(db, r); len(m) > 0 {
+			missing[r] = m
+		}
+	}
+
+	var suggestions []Suggestion
+	var pending []string
+	for len(suggestions) < n && len(missing) > 0 {
+		best, bestScore, bestPartial := "", 0, 0.0
+		var bestRecipes []*Recipe
+		for _, candidate := range purchases(db, missing) {
+			size := len(have.Items)
+			buying := &Inventory{Items: append(have.Items[:size:size], Component{Ingredient: candidate})}
+			score, partial := 0, 0.0
+			var unlocked []*Recipe
+			for r, m := range missing {
+				left := len(buying.Missing(db, r))
+				switch {
+				case left == 0:
+					score += weight(r)
+					unlocked = append(unlocked, r)
+				case left < len(m):
+					partial += float64(weight(r)) / float64(left)
+				}
+			}
+			if score > bestScore || (score == bestScore && partial > bestPartial) {
+				best, bestScore, bestPartial, bestRecipes = candidate, score, partial, unlocked
+			}
+		}
+		if best == "" {
+			break
+		}
+
+		have.Items = append(have.Items, Component{Ingredient: best})
+		for r := range missing {
+			if m := have.Missing(db, r); len(m) > 0 {
+				missing[r] = m
+			} else {
+				delete(missing, r)
+			}
+		}
+
+		if bestScore == 0 {
+			// it only helps along with a later purchase
+			pending = append(pending, best)
+			continue
+		}
+		sort.Slice(bestRecipes, func(i, j int) bool {
+			if bestRecipes[i].Rating != bestRecipes[j].Rating {
+				return bestRecipes[i].Rating > bestRecipes[j].Rating
+			}
+			return strings.ToLower(bestRecipes[i].Name) < strings.ToLower(bestRecipes[j].Name)
+		})
+		for _, p := range pending {
+			suggestions = append(suggestions, Suggestion{Ingredient: p})
+		}
+		pending = nil
+		suggestions = append(suggestions, Suggestion{Ingredient: best, Recipes: bestRecipes, Score: bestScore})
+	}
+
+	if len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	for len(suggestions) > 0 && suggestions[len(suggestions)-1].Score == 0 {
+		suggestions = suggestions[:len(suggestions)-1]
+	}
+	return suggestions
+}
+
+// purchases returns the ingredients that could be bought for the missing
+// components, by their names in db if they're in it, in a stable order.
+func purchases(db *IngredientDB, missing map[*Recipe][]Component) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range missing {
+		for _, c := range m {
+			name := strings.Join(strings.Fields(c.Ingredient), " ")
+			if in, ok := db.Resolve(c.Ingredient); ok {
+				name = in.Name
+			}
+			if key := normalizeIngredient(name); !seen[key] {
+				seen[key] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// weight is how much making r counts for.
+func weight(r *Recipe) int {
+	return max(r.Rating, 1)
+}
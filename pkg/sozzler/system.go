@@ -0,0 +1,65 @@
+package sozzler
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// System is a system of measurement recipes can be shown in. The zero System
+// leaves units as they're written.
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+func ParseSystem(s string) (System, error) {
+	switch System(s) {
+	case "", Metric, Imperial:
+		return System(s), nil
+	}
+	return "", fmt.Errorf("unknown system of measurement %q: use metric or imperial", s)
+}
+
+// InSystem returns a copy of components with every component measured in the
+// other system converted to system: ml for metric and oz for imperial. Amounts
+// are rounded to what a bartender can measure, the nearest 5 ml (2.5 ml under
+// 10 ml) or 1/4 oz. Bar measures like dash, and unmeasured components, are left
+// as they are.
+func InSystem(components []Component, system System) []Component {
+	converted := make([]Component, len(components))
+	copy(converted, components)
+	if system == "" {
+		return converted
+	}
+
+	to, increment := "ml", big.NewRat(5, 1)
+	if system == Imperial {
+		to, increment = "oz", big.NewRat(1, 4)
+	}
+
+	for i, c := range converted {
+		u, ok := LookupUnit(c.Unit)
+		if !ok || u.System == "" || u.System == system || c.Quantity.IsZero() {
+			continue
+		}
+
+		q, err := Convert(c.Quantity, c.Unit, to)
+		if err != nil {
+			continue
+		}
+
+		inc := increment
+		if system == Metric && q.Cmp(NewQuantity(10, 1)) < 0 {
+			inc = big.NewRat(5, 2)
+		}
+		q = q.Round(inc)
+		// bartenders measure milliliters in decimals, not fractions
+		q.decimal = system == Metric
+
+		converted[i].Quantity = q
+		converted[i].Unit = to
+	}
+	return converted
+}
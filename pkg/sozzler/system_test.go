@@ -0,0 +1,58 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInSystem(t *testing.T) {
+	components := []sozzler.Component{
+		*component("gin", "2", "oz"),
+		*component("lime juice", "1/4", "oz"),
+		*component("vermouth", "2", "cl"),
+		*component("absinthe", "3", "ml"),
+		*component("bitters", "2", "dash"),
+		*component("sugar", "1", "tsp"),
+		*component("lime wheel", "", ""),
+	}
+
+	testCases := []struct {
+		system sozzler.System
+		want   []string
+	}{
+		{
+			system: sozzler.Metric,
+			want:   []string{"60 ml", "7.5 ml", "2 cl", "3 ml", "2 dash", "1 tsp", " "},
+		},
+		{
+			system: sozzler.Imperial,
+			want:   []string{"2 oz", "1/4 oz", "3/4 oz", "1/4 oz", "2 dash", "1 tsp", " "},
+		},
+		{
+			system: "",
+			want:   []string{"2 oz", "1/4 oz", "2 cl", "3 ml", "2 dash", "1 tsp", " "},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(string(tC.system), func(t *testing.T) {
+			var got []string
+			for _, c := range sozzler.InSystem(components, tC.system) {
+				got = append(got, c.Quantity.String()+" "+c.Unit)
+			}
+			assert.Equal(t, tC.want, got)
+		})
+	}
+
+	assert.Equal(t, "oz", components[0].Unit, "components are not changed")
+}
+
+func TestParseSystem(t *testing.T) {
+	system, err := sozzler.ParseSystem("metric")
+	assert.NoError(t, err)
+	assert.Equal(t, sozzler.Metric, system)
+
+	_, err = sozzler.ParseSystem("furlongs")
+	assert.Error(t, err)
+}
@@ -2,28 +2,129 @@ package sozzler
 
 import (
 	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-type unit struct {
-	Name string `yaml:"name"`
+type Dimension string
+
+const (
+	Volume Dimension = "volume"
+	Mass   Dimension = "mass"
+	Count  Dimension = "count"
+)
+
+// Unit is a unit of measure from units.yaml.
+type Unit struct {
+	Name      string    `yaml:"name"`
+	Dimension Dimension `yaml:"dimension"`
+	System    System    `yaml:"system"`
+	// Factor is the size of the unit in its dimension's base unit: milliliters
+	// for volume, grams for mass, and 1 for count.
+	Factor  float64  `yaml:"-"`
+	Aliases []string `yaml:"aliases"`
+
+	// size is Factor, exactly.
+	size *big.Rat
 }
 
+var ErrIncompatibleUnits = errors.New("incompatible units")
+
 //go:embed units.yaml
 var unitsYAML []byte
 
-var knownUnits map[string]struct{}
+// knownUnits maps unit names and aliases, and their lower case forms, to units.
+var knownUnits map[string]*Unit
 
 func init() {
-	var units []unit
-	if err := yaml.Unmarshal(unitsYAML, &units); err != nil {
+	var entries []struct {
+		Unit   `yaml:",inline"`
+		Factor string `yaml:"factor"`
+	}
+	if err := yaml.Unmarshal(unitsYAML, &entries); err != nil {
 		panic(err)
 	}
 
-	knownUnits = make(map[string]struct{})
+	knownUnits = make(map[string]*Unit)
+	var units []*Unit
+	for _, e := range entries {
+		u := e.Unit
+		size, err := unitSize(e.Factor)
+		if err != nil {
+			panic(fmt.Errorf("unit %q: %w", u.Name, err))
+		}
+		u.size = size
+		u.Factor, _ = size.Float64()
+		knownUnits[u.Name] = &u
+		units = append(units, &u)
+	}
 
+	// exact spellings win over lower case ones, so "Tbsp" can't be shadowed
+	for _, u := range units {
+		for _, name := range append([]string{u.Name}, u.Aliases...) {
+			knownUnits[name] = u
+		}
+	}
 	for _, u := range units {
-		knownUnits[u.Name] = struct{}{}
+		for _, name := range append([]string{u.Name}, u.Aliases...) {
+			if _, ok := knownUnits[strings.ToLower(name)]; !ok {
+				knownUnits[strings.ToLower(name)] = u
+			}
+		}
+	}
+}
+
+// unitSize parses a units.yaml factor: a number, or a number of a unit defined
+// before it, like "1/6 oz".
+func unitSize(factor string) (*big.Rat, error) {
+	fields := strings.Fields(factor)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid factor %q", factor)
 	}
+	size, ok := new(big.Rat).SetString(fields[0])
+	if !ok || size.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid factor %q", factor)
+	}
+	if len(fields) == 2 {
+		of, ok := knownUnits[fields[1]]
+		if !ok {
+			return nil, fmt.Errorf("invalid factor %q: unknown unit %q", factor, fields[1])
+		}
+		size.Mul(size, of.size)
+	}
+	return size, nil
+}
+
+// LookupUnit finds a unit by name or alias, ignoring case if there's no exact
+// match.
+func LookupUnit(name string) (*Unit, bool) {
+	if u, ok := knownUnits[name]; ok {
+		return u, true
+	}
+	u, ok := knownUnits[strings.ToLower(name)]
+	return u, ok
+}
+
+// Convert converts q from one unit to another of the same dimension. Units may
+// be given by name or alias. Count units only convert to themselves.
+func Convert(q Quantity, from, to string) (Quantity, error) {
+	fromUnit, ok := LookupUnit(from)
+	if !ok {
+		return Quantity{}, fmt.Errorf("unknown unit %q", from)
+	}
+	toUnit, ok := LookupUnit(to)
+	if !ok {
+		return Quantity{}, fmt.Errorf("unknown unit %q", to)
+	}
+	// a sprig is not a wedge, though both count as 1
+	if fromUnit.Dimension != toUnit.Dimension || (fromUnit.Dimension == Count && fromUnit != toUnit) {
+		return Quantity{}, fmt.Errorf("can't convert %s to %s: %w", from, to, ErrIncompatibleUnits)
+	}
+
+	factor := new(big.Rat).Quo(fromUnit.size, toUnit.size)
+	return q.Scale(factor)
 }
@@ -0,0 +1,88 @@
+package sozzler_test
+
+import (
+	"mp/sozzler/pkg/sozzler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupUnit(t *testing.T) {
+	testCases := []struct {
+		given string
+		want  string
+		ok    bool
+	}{
+		{given: "oz", want: "oz", ok: true},
+		{given: "ounces", want: "oz", ok: true},
+		{given: "oz.", want: "oz", ok: true},
+		{given: "Tbsp", want: "tbsp", ok: true},
+		{given: "TSP", want: "tsp", ok: true},
+		{given: "dashes", want: "dash", ok: true},
+		{given: "g", want: "g", ok: true},
+		{given: "glug", ok: false},
+		{given: "", ok: false},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.given, func(t *testing.T) {
+			u, ok := sozzler.LookupUnit(tC.given)
+			assert.Equal(t, tC.ok, ok)
+			if tC.ok {
+				assert.Equal(t, tC.want, u.Name)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	testCases := []struct {
+		quantity string
+		from, to string
+		want     string
+		wantErr  bool
+	}{
+		{quantity: "2", from: "oz", to: "ml", want: "59 147/1000"},
+		{quantity: "1.5", from: "oz", to: "ml", want: "44.36025"},
+		{quantity: "3", from: "cl", to: "ml", want: "30"},
+		{quantity: "1", from: "tbsp", to: "tsp", want: "3"},
+		{quantity: "1", from: "cup", to: "ounces", want: "8"},
+		{quantity: "1/3", from: "oz", to: "tsp", want: "2"},
+		{quantity: "3", from: "tsp", to: "tbsp", want: "1"},
+		{quantity: "1/4", from: "cup", to: "tbsp", want: "4"},
+		{quantity: "1/2", from: "oz", to: "oz", want: "1/2"},
+		{quantity: "2", from: "sprig", to: "sprigs", want: "2"},
+		{quantity: "2", from: "sprig", to: "wedge", wantErr: true},
+		{quantity: "1", from: "oz", to: "g", wantErr: true},
+		{quantity: "1", from: "oz", to: "glug", wantErr: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.quantity+" "+tC.from+" to "+tC.to, func(t *testing.T) {
+			got, err := sozzler.Convert(*must(sozzler.ParseQuantity(tC.quantity)), tC.from, tC.to)
+			if tC.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tC.want, got.String())
+		})
+	}
+}
+
+func TestFancyOrder(t *testing.T) {
+	components := []sozzler.Component{
+		*component("mint", "", ""),
+		*component("sugar", "1", "tsp"),
+		*component("bitters", "2", "dash"),
+		*component("lime juice", "3/4", "oz"),
+		*component("cherry", "1", ""),
+		*component("rum", "4", "cl"),
+		*component("salt", "2", "g"),
+	}
+
+	var got []string
+	for _, c := range sozzler.FancyOrder(components) {
+		got = append(got, c.Ingredient)
+	}
+	assert.Equal(t, []string{"rum", "lime juice", "sugar", "bitters", "salt", "cherry", "mint"}, got)
+}